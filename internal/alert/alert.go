@@ -0,0 +1,72 @@
+// Package alert tells users through their alert channels when a monitor goes
+// down or recovers. Each channel type is a Notifier registered with the
+// Dispatcher, which queues a delivery per linked channel on every state
+// change and records each attempt to send it. The monitor's owner is told
+// too, without a channel, when an OwnerNotifier is registered.
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Channel types.
+const (
+	TypeWebhook   = "webhook"
+	TypeEmail     = "email"
+	TypeSlack     = "slack"
+	TypeDiscord   = "discord"
+	TypePagerDuty = "pagerduty"
+	TypeOpsgenie  = "opsgenie"
+)
+
+// Event types.
+const (
+	EventDown = "down"
+	EventUp   = "up"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSending   = "sending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// ErrUnknownType is returned for a channel type with no registered Notifier.
+var ErrUnknownType = errors.New("unknown alert channel type")
+
+// Event is a monitor state change to notify a channel about.
+type Event struct {
+	// DeliveryID is the same on every attempt at a delivery, so receivers
+	// can drop duplicates.
+	DeliveryID int64
+	Type       string
+	Monitor    storage.Monitor
+	// Check is the check that changed the monitor's state. It is nil if the
+	// check has since been deleted.
+	Check *storage.MonitorCheck
+}
+
+// Notifier sends events to one type of alert channel.
+type Notifier interface {
+	// Validate reports whether config is usable for a channel of this type.
+	// It is called before a channel is saved.
+	Validate(config json.RawMessage) error
+
+	// Notify sends event to channel. An error fails the attempt, which is
+	// retried with backoff until the delivery runs out of attempts.
+	Notify(ctx context.Context, channel storage.AlertChannel, event Event) error
+}
+
+// OwnerNotifier tells the owner of a monitor about its events, whether or
+// not the monitor is linked to any channels.
+type OwnerNotifier interface {
+	// NotifyOwner sends event to the owner of event.Monitor. Errors are
+	// retried like those of Notify.
+	NotifyOwner(ctx context.Context, event Event) error
+}
@@ -0,0 +1,47 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// CredentialRedacted stands in for a channel's credential in API
+// responses. Sending it back when updating a channel keeps the stored one.
+const CredentialRedacted = "********"
+
+// credentialFields names the config field that holds the credential of
+// each channel type with one. The credential is sealed into the channel's
+// secret rather than stored in its config.
+var credentialFields = map[string]string{
+	TypeSlack:     "webhook_url",
+	TypeDiscord:   "webhook_url",
+	TypePagerDuty: "routing_key",
+	TypeOpsgenie:  "api_key",
+}
+
+// CredentialField returns the config field holding the credential of
+// channelType, if it has one.
+func CredentialField(channelType string) (string, bool) {
+	field, ok := credentialFields[channelType]
+	return field, ok
+}
+
+// errNoCredential fails deliveries to a channel whose credential is
+// missing, which a retry cannot fix.
+var errNoCredential = errors.New("channel has no stored credential")
+
+// credential opens the sealed credential of channel.
+func credential(secrets *secret.Box, channel storage.AlertChannel) (string, error) {
+	if len(channel.Secret) == 0 {
+		return "", errNoCredential
+	}
+
+	plain, err := secrets.Open(channel.Secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to open credential: %w", err)
+	}
+	return string(plain), nil
+}
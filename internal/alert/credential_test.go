@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func TestNotifyWithoutCredential(t *testing.T) {
+	srv, reqs := recordingServer(t)
+	secrets := testSecrets(t)
+
+	tests := []struct {
+		name     string
+		notifier Notifier
+		config   string
+	}{
+		{"slack", NewSlackNotifier(secrets, ""), `{"webhook_url":"` + srv.URL + `"}`},
+		{"discord", NewDiscordNotifier(secrets, ""), `{"webhook_url":"` + srv.URL + `"}`},
+		{"pagerduty", NewPagerDutyNotifier(secrets, srv.URL, ""), `{"routing_key":"config-routing-key"}`},
+		{"opsgenie", NewOpsgenieNotifier(secrets, srv.URL, ""), `{"api_key":"config-api-key"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reqs = nil
+
+			// A credential in the config is never used in place of a
+			// sealed one
+			channel := storage.AlertChannel{Config: []byte(tt.config)}
+			err := tt.notifier.Notify(context.Background(), channel, Event{Type: EventDown, Monitor: storage.Monitor{ID: 7, Name: "api"}})
+			if !errors.Is(err, errNoCredential) {
+				t.Errorf("Notify() error = %v, want %v", err, errNoCredential)
+			}
+			if !permanent(err) {
+				t.Errorf("permanent(%v) = false, want true", err)
+			}
+			if len(*reqs) != 0 {
+				t.Errorf("got %d requests, want none", len(*reqs))
+			}
+		})
+	}
+}
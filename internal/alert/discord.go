@@ -0,0 +1,125 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Discord rejects embeds with longer text than these.
+const (
+	discordMaxTitleChars = 256
+	discordMaxFieldChars = 1024
+)
+
+// Embed colours
+const (
+	discordRed   = 0xcf222e
+	discordGreen = 0x1a7f37
+)
+
+// DiscordConfig is the config of a Discord channel.
+type DiscordConfig struct {
+	// WebhookURL is a Discord channel webhook. It is stored sealed, as the
+	// channel's secret.
+	WebhookURL string `json:"webhook_url"`
+}
+
+// DiscordNotifier posts embeds to a Discord webhook.
+type DiscordNotifier struct {
+	client  *http.Client
+	secrets *secret.Box
+	appURL  string
+}
+
+func NewDiscordNotifier(secrets *secret.Box, appURL string) *DiscordNotifier {
+	return &DiscordNotifier{
+		client:  &http.Client{Timeout: notifyTimeout},
+		secrets: secrets,
+		appURL:  appURL,
+	}
+}
+
+func (n *DiscordNotifier) Validate(raw json.RawMessage) error {
+	var cfg DiscordConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return err
+	}
+	return validateHTTPSURL("webhook_url", cfg.WebhookURL)
+}
+
+func (n *DiscordNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	webhookURL, err := credential(n.secrets, channel)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(discordPayload(newMessage(event, n.appURL)))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	return postJSON(ctx, n.client, webhookURL, nil, body)
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	URL       string         `json:"url,omitempty"`
+	Color     int            `json:"color"`
+	Fields    []discordField `json:"fields,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// discordPayload renders msg as a webhook message with one embed, titled
+// with a link back to the monitor. Discord rejects fields with no value, so
+// facts the message lacks, such as the URL of a heartbeat monitor, are left
+// out.
+func discordPayload(msg message) any {
+	embed := discordEmbed{
+		Title: truncate(msg.Title, discordMaxTitleChars),
+		URL:   msg.Link,
+		Color: discordGreen,
+	}
+	if msg.Down() {
+		embed.Color = discordRed
+	}
+
+	addField := func(name, value string, inline bool) {
+		if value != "" {
+			embed.Fields = append(embed.Fields, discordField{Name: name, Value: value, Inline: inline})
+		}
+	}
+	addField("Monitor", truncate(msg.MonitorName, discordMaxFieldChars), true)
+	addField("URL", truncate(msg.MonitorURL, discordMaxFieldChars), true)
+	if msg.StatusCode != 0 {
+		addField("Status code", fmt.Sprint(msg.StatusCode), true)
+	}
+	if msg.ResponseTimeMs != 0 {
+		addField("Response time", fmt.Sprintf("%d ms", msg.ResponseTimeMs), true)
+	}
+	if msg.ErrorMessage != "" {
+		// The code block costs 6 characters of the field
+		addField("Error", "```"+truncate(msg.ErrorMessage, discordMaxFieldChars-6)+"```", false)
+	}
+	if !msg.CheckedAt.IsZero() {
+		embed.Timestamp = msg.CheckedAt.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"username": "monitor-bee",
+		"embeds":   []discordEmbed{embed},
+	}
+}
+
+var _ Notifier = (*DiscordNotifier)(nil)
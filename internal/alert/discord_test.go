@@ -0,0 +1,108 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func TestDiscordNotifierNotify(t *testing.T) {
+	srv, reqs := recordingServer(t)
+	secrets := testSecrets(t)
+
+	api := storage.Monitor{ID: 7, Name: "api", Url: "https://api.example.com"}
+
+	tests := []struct {
+		name    string
+		channel storage.AlertChannel
+		event   Event
+		path    string
+		title   string
+		color   int
+		fields  []discordField
+	}{
+		{
+			name:    "sealed webhook url",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, srv.URL+"/sealed")},
+			event: Event{Type: EventDown, Monitor: api, Check: &storage.MonitorCheck{
+				StatusCode:   pgtype.Int4{Int32: 503, Valid: true},
+				ErrorMessage: pgtype.Text{String: "service unavailable", Valid: true},
+			}},
+			path:  "/sealed",
+			title: "api is down",
+			color: discordRed,
+			fields: []discordField{
+				{Name: "Monitor", Value: "api", Inline: true},
+				{Name: "URL", Value: "https://api.example.com", Inline: true},
+				{Name: "Status code", Value: "503", Inline: true},
+				{Name: "Error", Value: "```service unavailable```"},
+			},
+		},
+		{
+			name:    "recovery",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, srv.URL+"/sealed")},
+			event:   Event{Type: EventUp, Monitor: api},
+			path:    "/sealed",
+			title:   "api is back up",
+			color:   discordGreen,
+			fields: []discordField{
+				{Name: "Monitor", Value: "api", Inline: true},
+				{Name: "URL", Value: "https://api.example.com", Inline: true},
+			},
+		},
+		{
+			name:    "heartbeat monitor has no url",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, srv.URL+"/sealed")},
+			event: Event{Type: EventDown, Monitor: storage.Monitor{ID: 7, Name: "backup", Type: "heartbeat"}, Check: &storage.MonitorCheck{
+				ErrorMessage: pgtype.Text{String: "No ping received within 90 seconds", Valid: true},
+			}},
+			path:  "/sealed",
+			title: "backup is down",
+			color: discordRed,
+			fields: []discordField{
+				{Name: "Monitor", Value: "backup", Inline: true},
+				{Name: "Error", Value: "```No ping received within 90 seconds```"},
+			},
+		},
+	}
+
+	n := NewDiscordNotifier(secrets, "https://app.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reqs = nil
+
+			if err := n.Notify(context.Background(), tt.channel, tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*reqs))
+			}
+			req := (*reqs)[0]
+			if req.Path != tt.path {
+				t.Errorf("posted to %s, want %s", req.Path, tt.path)
+			}
+
+			var payload struct {
+				Embeds []discordEmbed `json:"embeds"`
+			}
+			if err := json.Unmarshal(req.Body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if len(payload.Embeds) != 1 {
+				t.Fatalf("payload %s has %d embeds, want 1", req.Body, len(payload.Embeds))
+			}
+			embed := payload.Embeds[0]
+			if embed.Title != tt.title || embed.Color != tt.color || embed.URL != "https://app.example.com/monitors/7" {
+				t.Errorf("embed = %+v, want title %q, color %#x and a link to the monitor", embed, tt.title, tt.color)
+			}
+			if !reflect.DeepEqual(embed.Fields, tt.fields) {
+				t.Errorf("fields = %+v, want %+v", embed.Fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/state"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	// pollInterval is how often the dispatcher looks for due deliveries.
+	pollInterval = time.Second
+
+	// maxClaimBatch caps how many deliveries are claimed in one poll.
+	maxClaimBatch = 50
+
+	// maxAttempts is how many times a delivery is tried before it fails.
+	maxAttempts = 5
+
+	// notifyTimeout bounds a single attempt to send a notification.
+	notifyTimeout = 30 * time.Second
+
+	// leaseSeconds is how long a claimed delivery is held before another
+	// instance may assume its sender crashed and claim it again.
+	leaseSeconds = int32(notifyTimeout/time.Second) + 30
+
+	// updateTimeout bounds the queue updates made after an attempt, so
+	// they still happen while shutting down.
+	updateTimeout = 5 * time.Second
+
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+
+	// maxRetryAfter caps the wait a rate-limited endpoint can ask for.
+	maxRetryAfter = time.Hour
+)
+
+var (
+	// errDisabled fails deliveries still queued for a channel that has
+	// since been disabled.
+	errDisabled = errors.New("channel is disabled")
+
+	// errNoOwnerNotifier fails deliveries to a monitor's owner left in the
+	// queue after owner alerts were turned off.
+	errNoOwnerNotifier = errors.New("owner alerts are not configured")
+)
+
+// Dispatcher queues deliveries when monitors change state and sends them
+// through the Notifier registered for each channel's type. It is a
+// state.Handler. Every instance runs one; SKIP LOCKED hands each delivery to
+// a single dispatcher.
+type Dispatcher struct {
+	store     *storage.Store
+	logger    *slog.Logger
+	notifiers map[string]Notifier
+	owner     OwnerNotifier
+}
+
+func NewDispatcher(store *storage.Store, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		logger:    logger,
+		notifiers: make(map[string]Notifier),
+	}
+}
+
+// Register makes n the Notifier for channels of channelType. It must be
+// called before the dispatcher is used.
+func (d *Dispatcher) Register(channelType string, n Notifier) {
+	d.notifiers[channelType] = n
+}
+
+// RegisterOwner makes n tell the owner of every monitor about its events,
+// on top of its channels. It must be called before the dispatcher is used.
+func (d *Dispatcher) RegisterOwner(n OwnerNotifier) {
+	d.owner = n
+}
+
+// Notifier returns the Notifier registered for channelType.
+func (d *Dispatcher) Notifier(channelType string) (Notifier, bool) {
+	n, ok := d.notifiers[channelType]
+	return n, ok
+}
+
+// Types returns the registered channel types, sorted.
+func (d *Dispatcher) Types() []string {
+	types := make([]string, 0, len(d.notifiers))
+	for t := range d.notifiers {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// HandleTransition queues a delivery to each enabled channel linked to the
+// monitor, and to its owner, when it goes down, or comes back up after being
+// down. A monitor coming up for the first time is not worth an alert.
+// Recoveries also go to the channels the monitor's escalation policy paged
+// during the outage.
+func (d *Dispatcher) HandleTransition(ctx context.Context, q *storage.Queries, t state.Transition) error {
+	var event string
+	switch {
+	case t.To == state.Down:
+		event = EventDown
+	case t.From == state.Down && t.To == state.Up:
+		event = EventUp
+	default:
+		return nil
+	}
+
+	n, err := q.EnqueueAlertDeliveries(ctx, storage.EnqueueAlertDeliveriesParams{
+		MonitorID:   t.Monitor.ID,
+		CheckID:     pgtype.Int4{Int32: t.Check.ID, Valid: true},
+		Event:       event,
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue alerts: %w", err)
+	}
+
+	if d.owner != nil {
+		err := q.EnqueueOwnerAlertDelivery(ctx, storage.EnqueueOwnerAlertDeliveryParams{
+			MonitorID:   t.Monitor.ID,
+			CheckID:     pgtype.Int4{Int32: t.Check.ID, Valid: true},
+			Event:       event,
+			MaxAttempts: maxAttempts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to queue owner alert: %w", err)
+		}
+		n++
+	}
+
+	if event == EventUp {
+		escalated, err := q.EnqueueEscalatedDeliveries(ctx, storage.EnqueueEscalatedDeliveriesParams{
+			MonitorID:   t.Monitor.ID,
+			CheckID:     pgtype.Int4{Int32: t.Check.ID, Valid: true},
+			Event:       event,
+			MaxAttempts: maxAttempts,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to queue escalated alerts: %w", err)
+		}
+		n += escalated
+	}
+
+	if n > 0 {
+		d.logger.Info("alerts queued", "monitor_id", t.Monitor.ID, "event", event, "channels", n)
+	}
+	return nil
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("alert dispatcher started", "channel_types", d.Types())
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	if n, err := d.store.FailExpiredAlertDeliveries(ctx); err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error("failed to fail expired alert deliveries", "error", err)
+		}
+	} else if n > 0 {
+		d.logger.Warn("alert deliveries failed after lease expiry", "count", n)
+	}
+
+	deliveries, err := d.store.ClaimAlertDeliveries(ctx, storage.ClaimAlertDeliveriesParams{
+		LeaseSeconds: leaseSeconds,
+		BatchSize:    maxClaimBatch,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error("failed to claim alert deliveries", "error", err)
+		}
+		return
+	}
+
+	// Channels are independent, so a slow one must not hold up the rest
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Go(func() { d.deliver(ctx, delivery) })
+	}
+	wg.Wait()
+}
+
+// deliver makes one attempt at delivery and records its outcome.
+func (d *Dispatcher) deliver(ctx context.Context, delivery storage.AlertDelivery) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.finish(ctx, delivery, 0, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	start := time.Now()
+	err := d.send(ctx, delivery)
+	d.finish(ctx, delivery, time.Since(start), err)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery storage.AlertDelivery) error {
+	notify, err := d.recipient(ctx, delivery)
+	if err != nil {
+		return err
+	}
+
+	mon, err := d.store.GetMonitor(ctx, delivery.MonitorID)
+	if err != nil {
+		return fmt.Errorf("failed to load monitor: %w", err)
+	}
+
+	event := Event{DeliveryID: delivery.ID, Type: delivery.Event, Monitor: mon}
+	if delivery.CheckID.Valid {
+		check, err := d.store.GetMonitorCheck(ctx, delivery.CheckID.Int32)
+		if err != nil {
+			return fmt.Errorf("failed to load check: %w", err)
+		}
+		event.Check = &check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	return notify(ctx, event)
+}
+
+// recipient returns the function that sends delivery: through its channel,
+// or to the monitor's owner for a delivery without one.
+func (d *Dispatcher) recipient(ctx context.Context, delivery storage.AlertDelivery) (func(context.Context, Event) error, error) {
+	if !delivery.ChannelID.Valid {
+		if d.owner == nil {
+			return nil, errNoOwnerNotifier
+		}
+		return d.owner.NotifyOwner, nil
+	}
+
+	channel, err := d.store.GetAlertChannel(ctx, delivery.ChannelID.Int32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channel: %w", err)
+	}
+
+	if !channel.Enabled {
+		return nil, errDisabled
+	}
+
+	n, ok := d.Notifier(channel.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, channel.Type)
+	}
+
+	return func(ctx context.Context, event Event) error {
+		return n.Notify(ctx, channel, event)
+	}, nil
+}
+
+// finish records the attempt, then marks the delivery delivered, retries it
+// with exponential backoff, or fails it once it has used all its attempts.
+func (d *Dispatcher) finish(ctx context.Context, delivery storage.AlertDelivery, took time.Duration, sendErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), updateTimeout)
+	defer cancel()
+
+	attempt := storage.CreateAlertDeliveryAttemptParams{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		Success:    sendErr == nil,
+		DurationMs: int32(took.Milliseconds()),
+	}
+	if sendErr != nil {
+		attempt.Error = pgtype.Text{String: sendErr.Error(), Valid: true}
+	}
+	if err := d.store.CreateAlertDeliveryAttempt(ctx, attempt); err != nil {
+		d.logger.Error("failed to record alert attempt", "delivery_id", delivery.ID, "error", err)
+	}
+
+	if sendErr == nil {
+		if err := d.store.CompleteAlertDelivery(ctx, delivery.ID); err != nil {
+			d.logger.Error("failed to complete alert delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		d.logger.Info("alert delivered",
+			"delivery_id", delivery.ID,
+			"channel_id", delivery.ChannelID.Int32,
+			"monitor_id", delivery.MonitorID,
+			"event", delivery.Event,
+		)
+		return
+	}
+
+	lastError := pgtype.Text{String: sendErr.Error(), Valid: true}
+
+	if delivery.Attempts >= delivery.MaxAttempts || permanent(sendErr) {
+		d.logger.Error("alert delivery failed",
+			"delivery_id", delivery.ID,
+			"channel_id", delivery.ChannelID.Int32,
+			"attempts", delivery.Attempts,
+			"error", sendErr,
+		)
+		err := d.store.FailAlertDelivery(ctx, storage.FailAlertDeliveryParams{
+			ID:        delivery.ID,
+			LastError: lastError,
+		})
+		if err != nil {
+			d.logger.Error("failed to fail alert delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	delay := retryDelay(delivery.Attempts)
+	var rateLimited *RetryAfterError
+	if errors.As(sendErr, &rateLimited) {
+		delay = min(max(rateLimited.After, time.Second), maxRetryAfter)
+	}
+	d.logger.Warn("alert delivery failed, retrying",
+		"delivery_id", delivery.ID,
+		"channel_id", delivery.ChannelID.Int32,
+		"attempts", delivery.Attempts,
+		"retry_in", delay,
+		"error", sendErr,
+	)
+	err := d.store.RetryAlertDelivery(ctx, storage.RetryAlertDeliveryParams{
+		RetryInSeconds: int32((delay + time.Second - 1) / time.Second),
+		LastError:      lastError,
+		ID:             delivery.ID,
+	})
+	if err != nil {
+		d.logger.Error("failed to retry alert delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// permanent reports whether err will not go away on retry.
+func permanent(err error) bool {
+	return errors.Is(err, ErrUnknownType) ||
+		errors.Is(err, errDisabled) ||
+		errors.Is(err, errNoOwnerNotifier) ||
+		errors.Is(err, errNoCredential)
+}
+
+// retryDelay doubles from retryBaseDelay with every attempt, up to
+// retryMaxDelay.
+func retryDelay(attempts int32) time.Duration {
+	delay := retryBaseDelay
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+var _ state.Handler = (*Dispatcher)(nil)
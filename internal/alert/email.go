@@ -0,0 +1,149 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	texttemplate "text/template"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	emailHTML = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/email.html"))
+	emailText = texttemplate.Must(texttemplate.ParseFS(templates, "templates/email.txt"))
+)
+
+// Mail is an email with plain-text and HTML bodies.
+type Mail struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender sends mail. SMTPSender is the one used in production.
+type Sender interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// maxEmailRecipients caps the addresses on one email channel.
+const maxEmailRecipients = 50
+
+// EmailConfig is the config of an email channel.
+type EmailConfig struct {
+	Recipients []string `json:"recipients"`
+}
+
+// EmailNotifier emails the recipients of email channels. It is also the
+// OwnerNotifier, emailing the owner of a monitor about every event.
+type EmailNotifier struct {
+	store  *storage.Store
+	sender Sender
+	appURL string
+}
+
+func NewEmailNotifier(store *storage.Store, sender Sender, appURL string) *EmailNotifier {
+	return &EmailNotifier{
+		store:  store,
+		sender: sender,
+		appURL: appURL,
+	}
+}
+
+func (n *EmailNotifier) Validate(raw json.RawMessage) error {
+	var cfg EmailConfig
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return err
+	}
+
+	if len(cfg.Recipients) == 0 {
+		return errors.New("recipients is required")
+	}
+	if len(cfg.Recipients) > maxEmailRecipients {
+		return fmt.Errorf("at most %d recipients are allowed", maxEmailRecipients)
+	}
+	_, err := recipientAddresses(cfg.Recipients)
+	return err
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	var cfg EmailConfig
+	if err := json.Unmarshal(channel.Config, &cfg); err != nil {
+		return fmt.Errorf("invalid email config: %w", err)
+	}
+	to, err := recipientAddresses(cfg.Recipients)
+	if err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		return errors.New("channel has no recipients")
+	}
+
+	return n.send(ctx, to, event)
+}
+
+// NotifyOwner emails event to the owner of the monitor.
+func (n *EmailNotifier) NotifyOwner(ctx context.Context, event Event) error {
+	owner, err := n.store.GetUserByID(ctx, event.Monitor.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load monitor owner: %w", err)
+	}
+
+	return n.send(ctx, []string{owner.Email}, event)
+}
+
+func (n *EmailNotifier) send(ctx context.Context, to []string, event Event) error {
+	mail, err := renderMail(newMessage(event, n.appURL))
+	if err != nil {
+		return err
+	}
+	mail.To = to
+
+	return n.sender.Send(ctx, mail)
+}
+
+// recipientAddresses returns the bare address of each recipient, which may
+// be given with a display name.
+func recipientAddresses(recipients []string) ([]string, error) {
+	addrs := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", r, err)
+		}
+		addrs = append(addrs, addr.Address)
+	}
+	return addrs, nil
+}
+
+func renderMail(msg message) (Mail, error) {
+	var text, html bytes.Buffer
+	if err := emailText.Execute(&text, msg); err != nil {
+		return Mail{}, fmt.Errorf("failed to render email: %w", err)
+	}
+	if err := emailHTML.Execute(&html, msg); err != nil {
+		return Mail{}, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	return Mail{
+		Subject: "[monitor-bee] " + msg.Title,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+var (
+	_ Notifier      = (*EmailNotifier)(nil)
+	_ OwnerNotifier = (*EmailNotifier)(nil)
+)
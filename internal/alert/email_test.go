@@ -0,0 +1,185 @@
+package alert
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// fakeSender records the mail it is asked to send.
+type fakeSender struct {
+	sent []Mail
+}
+
+func (s *fakeSender) Send(ctx context.Context, mail Mail) error {
+	s.sent = append(s.sent, mail)
+	return nil
+}
+
+// ownerDB answers the owner lookup of EmailNotifier with a fixed user. Any
+// other query panics on the nil embedded DBTX.
+type ownerDB struct {
+	storage.DBTX
+	email string
+}
+
+func (db ownerDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return ownerRow{id: args[0].(int32), email: db.email}
+}
+
+type ownerRow struct {
+	id    int32
+	email string
+}
+
+func (r ownerRow) Scan(dest ...any) error {
+	*dest[0].(*int32) = r.id
+	*dest[1].(*string) = r.email
+	return nil
+}
+
+func TestEmailNotifierNotify(t *testing.T) {
+	mon := storage.Monitor{ID: 7, UserID: 3, Name: "api", Url: "https://api.example.com"}
+	check := &storage.MonitorCheck{
+		StatusCode:     pgtype.Int4{Int32: 503, Valid: true},
+		ErrorMessage:   pgtype.Text{String: "service unavailable", Valid: true},
+		ResponseTimeMs: pgtype.Int4{Int32: 120, Valid: true},
+		CheckedAt:      pgtype.Timestamp{Time: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name        string
+		event       Event
+		subject     string
+		wantText    []string
+		notWantText []string
+		wantHTML    []string
+	}{
+		{
+			name:    "down",
+			event:   Event{Type: EventDown, Monitor: mon, Check: check},
+			subject: "[monitor-bee] api is down",
+			wantText: []string{
+				"api is down\n",
+				"Monitor: api\n",
+				"URL: https://api.example.com\n",
+				"Status code: 503\n",
+				"Error: service unavailable\n",
+				"Response time: 120 ms\n",
+				"Checked at: 2026-05-01 12:00:00 UTC\n",
+				"View the monitor: https://app.example.com/monitors/7\n",
+			},
+			wantHTML: []string{
+				"color: #cf222e;\">api is down</h2>",
+				"<td style=\"padding: 4px 0;\">service unavailable</td>",
+				"href=\"https://app.example.com/monitors/7\"",
+			},
+		},
+		{
+			name:        "up without check",
+			event:       Event{Type: EventUp, Monitor: mon},
+			subject:     "[monitor-bee] api is back up",
+			wantText:    []string{"api is back up\n", "Monitor: api\n"},
+			notWantText: []string{"Status code:", "Error:", "Response time:", "Checked at:"},
+			wantHTML:    []string{"color: #1a7f37;\">api is back up</h2>"},
+		},
+		{
+			name:     "html escapes the monitor name",
+			event:    Event{Type: EventDown, Monitor: storage.Monitor{ID: 8, UserID: 3, Name: "<b>shop</b>"}},
+			subject:  "[monitor-bee] <b>shop</b> is down",
+			wantText: []string{"Monitor: <b>shop</b>\n"},
+			wantHTML: []string{"&lt;b&gt;shop&lt;/b&gt; is down"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{}
+			n := NewEmailNotifier(nil, sender, "https://app.example.com/")
+
+			channel := storage.AlertChannel{Config: []byte(`{"recipients":["Ops <ops@example.com>","oncall@example.com"]}`)}
+			if err := n.Notify(context.Background(), channel, tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(sender.sent) != 1 {
+				t.Fatalf("sent %d mails, want 1", len(sender.sent))
+			}
+			mail := sender.sent[0]
+			if want := []string{"ops@example.com", "oncall@example.com"}; !slices.Equal(mail.To, want) {
+				t.Errorf("To = %v, want %v", mail.To, want)
+			}
+			if mail.Subject != tt.subject {
+				t.Errorf("Subject = %q, want %q", mail.Subject, tt.subject)
+			}
+			for _, s := range tt.wantText {
+				if !strings.Contains(mail.Text, s) {
+					t.Errorf("text body does not contain %q:\n%s", s, mail.Text)
+				}
+			}
+			for _, s := range tt.notWantText {
+				if strings.Contains(mail.Text, s) {
+					t.Errorf("text body contains %q:\n%s", s, mail.Text)
+				}
+			}
+			for _, s := range tt.wantHTML {
+				if !strings.Contains(mail.HTML, s) {
+					t.Errorf("HTML body does not contain %q:\n%s", s, mail.HTML)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailNotifierNotifyOwner(t *testing.T) {
+	sender := &fakeSender{}
+	store := &storage.Store{Queries: storage.New(ownerDB{email: "owner@example.com"})}
+	n := NewEmailNotifier(store, sender, "https://app.example.com")
+
+	event := Event{Type: EventDown, Monitor: storage.Monitor{ID: 7, UserID: 3, Name: "api"}}
+	if err := n.NotifyOwner(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d mails, want 1", len(sender.sent))
+	}
+	mail := sender.sent[0]
+	if want := []string{"owner@example.com"}; !slices.Equal(mail.To, want) {
+		t.Errorf("To = %v, want %v", mail.To, want)
+	}
+	if mail.Subject != "[monitor-bee] api is down" {
+		t.Errorf("Subject = %q, want %q", mail.Subject, "[monitor-bee] api is down")
+	}
+}
+
+func TestEmailNotifierValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"recipients", `{"recipients":["ops@example.com","On-call <oncall@example.com>"]}`, false},
+		{"no recipients", `{}`, true},
+		{"empty recipients", `{"recipients":[]}`, true},
+		{"invalid recipient", `{"recipients":["ops@example.com","not an address"]}`, true},
+		{"too many recipients", `{"recipients":[` + strings.Repeat(`"ops@example.com",`, maxEmailRecipients) + `"ops@example.com"]}`, true},
+		{"unknown field", `{"recipients":["ops@example.com"],"to":"someone@example.com"}`, true},
+		{"invalid json", `{`, true},
+	}
+
+	n := NewEmailNotifier(nil, &fakeSender{}, "https://app.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := n.Validate([]byte(tt.config)); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%s) error = %v, wantErr %v", tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
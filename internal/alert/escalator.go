@@ -0,0 +1,202 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/incident"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	// escalatePollInterval is how often the escalator looks for due
+	// levels. Delays are in minutes, so this need not be tight.
+	escalatePollInterval = 10 * time.Second
+
+	// maxEscalateBatch caps how many escalations are advanced in one poll.
+	maxEscalateBatch = 50
+)
+
+// Escalator notifies the levels of an incident's escalation policy as they
+// fall due, queueing a delivery to each of a level's channels. An incident
+// stops escalating once it is acknowledged or resolved, or its last level
+// has been notified. Every instance runs one; SKIP LOCKED hands each
+// escalation to a single escalator.
+type Escalator struct {
+	store  *storage.Store
+	logger *slog.Logger
+}
+
+func NewEscalator(store *storage.Store, logger *slog.Logger) *Escalator {
+	return &Escalator{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Run escalates incidents until ctx is cancelled.
+func (e *Escalator) Run(ctx context.Context) {
+	e.logger.Info("escalator started")
+
+	ticker := time.NewTicker(escalatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.poll(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Error("failed to escalate incidents", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll notifies the next level of every due escalation. Each is advanced in
+// its own transaction, which also queues its deliveries, so a level is
+// notified once and one failing escalation does not hold up the rest.
+func (e *Escalator) poll(ctx context.Context) error {
+	due, err := e.store.ListDueEscalations(ctx, maxEscalateBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, incidentID := range due {
+		err := e.store.ExecTx(ctx, func(q *storage.Queries) error {
+			esc, err := q.ClaimDueEscalation(ctx, incidentID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Another escalator has it, or it moved on since listed
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return e.escalate(ctx, q, esc)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// Left due, so it is retried on the next poll
+			e.logger.Error("failed to escalate incident", "incident_id", incidentID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (e *Escalator) escalate(ctx context.Context, q *storage.Queries, esc storage.ClaimDueEscalationRow) error {
+	// The incident service stops escalations as it acknowledges and
+	// resolves incidents; this only catches ones it missed
+	switch {
+	case esc.State != incident.Open:
+		return e.stop(ctx, q, esc, incident.StopResolved)
+	case esc.AcknowledgedAt.Valid:
+		return e.stop(ctx, q, esc, incident.StopAcknowledged)
+	}
+
+	level, err := q.GetEscalationLevel(ctx, storage.GetEscalationLevelParams{
+		PolicyID: esc.PolicyID,
+		Position: esc.NextLevel,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The policy lost levels since the escalation started
+		return e.stop(ctx, q, esc, incident.StopExhausted)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load level: %w", err)
+	}
+
+	// Alert with the monitor's latest check, which says how it is failing
+	// now rather than when the incident started
+	var checkID pgtype.Int4
+	check, err := q.GetLatestMonitorCheck(ctx, esc.MonitorID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to load latest check: %w", err)
+	}
+	if err == nil {
+		checkID = pgtype.Int4{Int32: check.ID, Valid: true}
+	}
+
+	n, err := q.EnqueueEscalationDeliveries(ctx, storage.EnqueueEscalationDeliveriesParams{
+		LevelID:     level.ID,
+		MonitorID:   esc.MonitorID,
+		IncidentID:  esc.IncidentID,
+		CheckID:     checkID,
+		Event:       EventDown,
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue alerts: %w", err)
+	}
+
+	_, err = q.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+		IncidentID: esc.IncidentID,
+		Type:       incident.EventEscalated,
+		CheckID:    checkID,
+		Message: pgtype.Text{
+			String: fmt.Sprintf("Escalated to level %d, notifying %d channels", level.Position+1, n),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record escalation: %w", err)
+	}
+
+	e.logger.Warn("incident escalated",
+		"incident_id", esc.IncidentID,
+		"monitor_id", esc.MonitorID,
+		"level", level.Position+1,
+		"channels", n,
+	)
+
+	next, err := q.GetEscalationLevel(ctx, storage.GetEscalationLevelParams{
+		PolicyID: esc.PolicyID,
+		Position: esc.NextLevel + 1,
+	})
+	last := errors.Is(err, pgx.ErrNoRows)
+	if err != nil && !last {
+		return fmt.Errorf("failed to load next level: %w", err)
+	}
+
+	// Advance even past the last level, so next_level counts the levels
+	// notified
+	nextAt := esc.NextAt
+	if !last {
+		nextAt = pgtype.Timestamp{
+			Time:  esc.StartedAt.Time.Add(time.Duration(next.DelayMinutes) * time.Minute),
+			Valid: true,
+		}
+	}
+	err = q.AdvanceEscalation(ctx, storage.AdvanceEscalationParams{
+		IncidentID: esc.IncidentID,
+		NextAt:     nextAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to advance escalation: %w", err)
+	}
+
+	if last {
+		return e.stop(ctx, q, esc, incident.StopExhausted)
+	}
+	return nil
+}
+
+func (e *Escalator) stop(ctx context.Context, q *storage.Queries, esc storage.ClaimDueEscalationRow, reason string) error {
+	err := q.StopEscalation(ctx, storage.StopEscalationParams{
+		IncidentID: esc.IncidentID,
+		StopReason: pgtype.Text{String: reason, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stop escalation: %w", err)
+	}
+
+	e.logger.Info("escalation stopped", "incident_id", esc.IncidentID, "reason", reason)
+	return nil
+}
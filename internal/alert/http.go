@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodyBytes caps how much of a failed response is kept in the
+// delivery log.
+const maxErrorBodyBytes = 512
+
+// RetryAfterError is returned when an endpoint rate limits a delivery. The
+// dispatcher waits After before retrying, instead of its usual backoff.
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.After)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// postJSON posts body to url and fails on any status other than 2xx. A 429
+// becomes a RetryAfterError when the response says how long to wait.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "monitor-bee")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		// Drain the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if after, ok := retryAfter(resp.Header); ok {
+			return &RetryAfterError{After: after, Err: err}
+		}
+	}
+	return err
+}
+
+// retryAfter reads how long a rate-limited client must wait from the
+// standard Retry-After header, in seconds or as a date, or from Discord's
+// X-RateLimit-Reset-After, in fractional seconds.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+
+	if v := h.Get("X-RateLimit-Reset-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+	}
+
+	return 0, false
+}
+
+// validateHTTPSURL checks that the named config field is an https URL.
+func validateHTTPSURL(field, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%s must be an https URL", field)
+	}
+	return nil
+}
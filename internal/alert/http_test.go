@@ -0,0 +1,92 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		wantOK bool
+	}{
+		{"none", http.Header{}, 0, false},
+		{"seconds", http.Header{"Retry-After": {"30"}}, 30 * time.Second, true},
+		{"zero seconds", http.Header{"Retry-After": {"0"}}, 0, true},
+		{"past date", http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}}, 0, true},
+		{"negative seconds", http.Header{"Retry-After": {"-5"}}, 0, false},
+		{"garbage", http.Header{"Retry-After": {"soon"}}, 0, false},
+		{"discord reset", http.Header{"X-Ratelimit-Reset-After": {"1.5"}}, 1500 * time.Millisecond, true},
+		{"standard header wins", http.Header{"Retry-After": {"2"}, "X-Ratelimit-Reset-After": {"9"}}, 2 * time.Second, true},
+		{"falls back to discord reset", http.Header{"Retry-After": {"soon"}, "X-Ratelimit-Reset-After": {"0.25"}}, 250 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryAfterFutureDate(t *testing.T) {
+	h := http.Header{"Retry-After": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}
+
+	got, ok := retryAfter(h)
+	if !ok || got <= 58*time.Second || got > time.Minute {
+		t.Errorf("retryAfter() = %v, %v, want about a minute", got, ok)
+	}
+}
+
+func TestPostJSONStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		wantErr   bool
+		wantAfter time.Duration
+	}{
+		{"ok", http.StatusOK, nil, false, 0},
+		{"accepted", http.StatusAccepted, nil, false, 0},
+		{"server error", http.StatusInternalServerError, nil, true, 0},
+		{"rate limited", http.StatusTooManyRequests, http.Header{"Retry-After": {"3"}}, true, 3 * time.Second},
+		{"rate limited without delay", http.StatusTooManyRequests, nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", ct)
+				}
+				for name, values := range tt.header {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := postJSON(context.Background(), srv.Client(), srv.URL, nil, []byte(`{}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var rae *RetryAfterError
+			isRetry := errors.As(err, &rae)
+			if isRetry != (tt.wantAfter != 0) {
+				t.Fatalf("postJSON() error = %v, want RetryAfterError %v", err, tt.wantAfter != 0)
+			}
+			if isRetry && rae.After != tt.wantAfter {
+				t.Errorf("RetryAfterError.After = %v, want %v", rae.After, tt.wantAfter)
+			}
+		})
+	}
+}
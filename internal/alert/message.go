@@ -0,0 +1,82 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// message is what an alert says about an event, whatever format it is sent
+// in.
+type message struct {
+	Event          string
+	Title          string
+	MonitorName    string
+	MonitorURL     string
+	StatusCode     int
+	ErrorMessage   string
+	ResponseTimeMs int
+	CheckedAt      time.Time
+	// Link opens the monitor in monitor-bee
+	Link string
+}
+
+func newMessage(event Event, appURL string) message {
+	mon := event.Monitor
+
+	msg := message{
+		Event:       event.Type,
+		MonitorName: mon.Name,
+		MonitorURL:  mon.Url,
+		Link:        fmt.Sprintf("%s/monitors/%d", strings.TrimRight(appURL, "/"), mon.ID),
+	}
+
+	if event.Type == EventDown {
+		msg.Title = fmt.Sprintf("%s is down", mon.Name)
+	} else {
+		msg.Title = fmt.Sprintf("%s is back up", mon.Name)
+	}
+
+	if c := event.Check; c != nil {
+		msg.StatusCode = int(c.StatusCode.Int32)
+		msg.ErrorMessage = c.ErrorMessage.String
+		msg.ResponseTimeMs = int(c.ResponseTimeMs.Int32)
+		msg.CheckedAt = c.CheckedAt.Time
+	}
+
+	return msg
+}
+
+// Down reports whether the message is about a monitor going down.
+func (m message) Down() bool {
+	return m.Event == EventDown
+}
+
+// Details returns the message's facts as name/value pairs, for services
+// that show custom fields.
+func (m message) Details() map[string]string {
+	details := map[string]string{
+		"monitor": m.MonitorName,
+		"url":     m.MonitorURL,
+		"link":    m.Link,
+	}
+	if m.StatusCode != 0 {
+		details["status_code"] = fmt.Sprint(m.StatusCode)
+	}
+	if m.ErrorMessage != "" {
+		details["error_message"] = m.ErrorMessage
+	}
+	if m.ResponseTimeMs != 0 {
+		details["response_time_ms"] = fmt.Sprint(m.ResponseTimeMs)
+	}
+	if !m.CheckedAt.IsZero() {
+		details["checked_at"] = m.CheckedAt.UTC().Format(time.RFC3339)
+	}
+	return details
+}
+
+// dedupKey identifies a monitor's outage to paging services, so repeated
+// triggers update one alert and a recovery resolves it.
+func dedupKey(monitorID int32) string {
+	return fmt.Sprintf("monitor-bee-monitor-%d", monitorID)
+}
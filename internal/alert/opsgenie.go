@@ -0,0 +1,136 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Opsgenie rejects longer messages and descriptions than these.
+const (
+	opsgenieMaxMessageChars     = 130
+	opsgenieMaxDescriptionChars = 15000
+)
+
+var opsgeniePriorities = []string{"P1", "P2", "P3", "P4", "P5"}
+
+// OpsgenieConfig is the config of an Opsgenie channel.
+type OpsgenieConfig struct {
+	// APIKey is the key of an Opsgenie API integration. It is stored
+	// sealed, as the channel's secret.
+	APIKey string `json:"api_key"`
+	// Priority of created alerts; P1 when empty.
+	Priority string `json:"priority,omitempty"`
+}
+
+// OpsgenieNotifier creates an Opsgenie alert when a monitor goes down and
+// closes it when the monitor recovers. Alerts are found again by their
+// alias, which Opsgenie also uses to drop duplicates.
+type OpsgenieNotifier struct {
+	client  *http.Client
+	secrets *secret.Box
+	baseURL string
+	appURL  string
+}
+
+func NewOpsgenieNotifier(secrets *secret.Box, baseURL, appURL string) *OpsgenieNotifier {
+	return &OpsgenieNotifier{
+		client:  &http.Client{Timeout: notifyTimeout},
+		secrets: secrets,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		appURL:  appURL,
+	}
+}
+
+func parseOpsgenieConfig(raw json.RawMessage) (OpsgenieConfig, error) {
+	var cfg OpsgenieConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Priority == "" {
+		cfg.Priority = "P1"
+	}
+	return cfg, nil
+}
+
+func (n *OpsgenieNotifier) Validate(raw json.RawMessage) error {
+	cfg, err := parseOpsgenieConfig(raw)
+	if err != nil {
+		return err
+	}
+
+	if cfg.APIKey == "" {
+		return errors.New("api_key is required")
+	}
+
+	if !slices.Contains(opsgeniePriorities, cfg.Priority) {
+		return fmt.Errorf("priority must be one of: %s", strings.Join(opsgeniePriorities, ", "))
+	}
+	return nil
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+func (n *OpsgenieNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	cfg, err := parseOpsgenieConfig(channel.Config)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	apiKey, err := credential(n.secrets, channel)
+	if err != nil {
+		return err
+	}
+
+	msg := newMessage(event, n.appURL)
+	alias := dedupKey(event.Monitor.ID)
+
+	header := make(http.Header)
+	header.Set("Authorization", "GenieKey "+apiKey)
+
+	if !msg.Down() {
+		body, err := json.Marshal(opsgenieClose{Source: "monitor-bee", Note: msg.Title})
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		endpoint := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", n.baseURL, url.PathEscape(alias))
+		return postJSON(ctx, n.client, endpoint, header, body)
+	}
+
+	body, err := json.Marshal(opsgenieAlert{
+		Message:     truncate(msg.Title, opsgenieMaxMessageChars),
+		Alias:       alias,
+		Description: truncate(summary(msg)+"\n\n"+msg.Link, opsgenieMaxDescriptionChars),
+		Details:     msg.Details(),
+		Source:      "monitor-bee",
+		Priority:    cfg.Priority,
+		Tags:        []string{"monitor-bee"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	return postJSON(ctx, n.client, n.baseURL+"/v2/alerts", header, body)
+}
+
+var _ Notifier = (*OpsgenieNotifier)(nil)
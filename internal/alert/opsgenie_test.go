@@ -0,0 +1,117 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func TestOpsgenieNotifierNotify(t *testing.T) {
+	srv, reqs := recordingServer(t)
+	secrets := testSecrets(t)
+
+	mon := storage.Monitor{ID: 7, Name: "api", Url: "https://api.example.com"}
+	check := &storage.MonitorCheck{
+		ErrorMessage: pgtype.Text{String: "connection refused", Valid: true},
+	}
+
+	tests := []struct {
+		name    string
+		channel storage.AlertChannel
+		event   Event
+		path    string
+		auth    string
+		want    any
+	}{
+		{
+			name: "create",
+			channel: storage.AlertChannel{
+				Config: []byte(`{"priority":"P2"}`),
+				Secret: seal(t, secrets, "sealed-api-key"),
+			},
+			event: Event{Type: EventDown, Monitor: mon, Check: check},
+			path:  "/v2/alerts",
+			auth:  "GenieKey sealed-api-key",
+			want: &opsgenieAlert{
+				Message:     "api is down",
+				Alias:       "monitor-bee-monitor-7",
+				Description: "api is down: connection refused\n\nhttps://app.example.com/monitors/7",
+				Details: map[string]string{
+					"monitor":       "api",
+					"url":           "https://api.example.com",
+					"link":          "https://app.example.com/monitors/7",
+					"error_message": "connection refused",
+				},
+				Source:   "monitor-bee",
+				Priority: "P2",
+				Tags:     []string{"monitor-bee"},
+			},
+		},
+		{
+			name: "create truncates message",
+			channel: storage.AlertChannel{
+				Config: []byte(`{}`),
+				Secret: seal(t, secrets, "sealed-api-key"),
+			},
+			event: Event{Type: EventDown, Monitor: storage.Monitor{ID: 9, Name: strings.Repeat("m", 200)}},
+			path:  "/v2/alerts",
+			auth:  "GenieKey sealed-api-key",
+			want: &opsgenieAlert{
+				Message:     strings.Repeat("m", opsgenieMaxMessageChars-1) + "…",
+				Alias:       "monitor-bee-monitor-9",
+				Description: strings.Repeat("m", 200) + " is down\n\nhttps://app.example.com/monitors/9",
+				Details: map[string]string{
+					"monitor": strings.Repeat("m", 200),
+					"url":     "",
+					"link":    "https://app.example.com/monitors/9",
+				},
+				Source:   "monitor-bee",
+				Priority: "P1",
+				Tags:     []string{"monitor-bee"},
+			},
+		},
+		{
+			name:    "close",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, "sealed-api-key")},
+			event:   Event{Type: EventUp, Monitor: mon},
+			path:    "/v2/alerts/monitor-bee-monitor-7/close?identifierType=alias",
+			auth:    "GenieKey sealed-api-key",
+			want:    &opsgenieClose{Source: "monitor-bee", Note: "api is back up"},
+		},
+	}
+
+	n := NewOpsgenieNotifier(secrets, srv.URL, "https://app.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reqs = nil
+
+			if err := n.Notify(context.Background(), tt.channel, tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*reqs))
+			}
+			req := (*reqs)[0]
+			if req.Path != tt.path {
+				t.Errorf("posted to %s, want %s", req.Path, tt.path)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.auth {
+				t.Errorf("Authorization = %q, want %q", got, tt.auth)
+			}
+
+			got := reflect.New(reflect.TypeOf(tt.want).Elem()).Interface()
+			if err := json.Unmarshal(req.Body, got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %s\nwant %+v", req.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// pagerDutyMaxSummaryChars is the longest summary PagerDuty accepts.
+const pagerDutyMaxSummaryChars = 1024
+
+// pagerDutySeverities are the severities the Events API accepts.
+var pagerDutySeverities = []string{"critical", "error", "warning", "info"}
+
+// PagerDutyConfig is the config of a PagerDuty channel.
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of an Events API v2 integration.
+	// It is stored sealed, as the channel's secret.
+	RoutingKey string `json:"routing_key"`
+	// Severity of triggered alerts; critical when empty.
+	Severity string `json:"severity,omitempty"`
+}
+
+// PagerDutyNotifier triggers a PagerDuty alert when a monitor goes down and
+// resolves it when the monitor recovers, through the Events API v2.
+type PagerDutyNotifier struct {
+	client  *http.Client
+	secrets *secret.Box
+	baseURL string
+	appURL  string
+}
+
+func NewPagerDutyNotifier(secrets *secret.Box, baseURL, appURL string) *PagerDutyNotifier {
+	return &PagerDutyNotifier{
+		client:  &http.Client{Timeout: notifyTimeout},
+		secrets: secrets,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		appURL:  appURL,
+	}
+}
+
+func parsePagerDutyConfig(raw json.RawMessage) (PagerDutyConfig, error) {
+	var cfg PagerDutyConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Severity == "" {
+		cfg.Severity = "critical"
+	}
+	return cfg, nil
+}
+
+func (n *PagerDutyNotifier) Validate(raw json.RawMessage) error {
+	cfg, err := parsePagerDutyConfig(raw)
+	if err != nil {
+		return err
+	}
+
+	if cfg.RoutingKey == "" {
+		return errors.New("routing_key is required")
+	}
+
+	if !slices.Contains(pagerDutySeverities, cfg.Severity) {
+		return fmt.Errorf("severity must be one of: %s", strings.Join(pagerDutySeverities, ", "))
+	}
+	return nil
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Client      string            `json:"client,omitempty"`
+	ClientURL   string            `json:"client_url,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	cfg, err := parsePagerDutyConfig(channel.Config)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	routingKey, err := credential(n.secrets, channel)
+	if err != nil {
+		return err
+	}
+
+	msg := newMessage(event, n.appURL)
+	pdEvent := pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey(event.Monitor.ID),
+	}
+
+	if msg.Down() {
+		pdEvent.EventAction = "trigger"
+		pdEvent.Client = "monitor-bee"
+		pdEvent.ClientURL = msg.Link
+		pdEvent.Links = []pagerDutyLink{{Href: msg.Link, Text: "View monitor"}}
+		pdEvent.Payload = &pagerDutyPayload{
+			Summary:       truncate(summary(msg), pagerDutyMaxSummaryChars),
+			Source:        cmp.Or(msg.MonitorURL, msg.MonitorName),
+			Severity:      cfg.Severity,
+			CustomDetails: msg.Details(),
+		}
+		if !msg.CheckedAt.IsZero() {
+			pdEvent.Payload.Timestamp = msg.CheckedAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	body, err := json.Marshal(pdEvent)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return postJSON(ctx, n.client, n.baseURL+"/v2/enqueue", nil, body)
+}
+
+// summary is the one-line description of an alert, with the error when
+// there is one.
+func summary(msg message) string {
+	if msg.ErrorMessage == "" {
+		return msg.Title
+	}
+	return msg.Title + ": " + msg.ErrorMessage
+}
+
+var _ Notifier = (*PagerDutyNotifier)(nil)
@@ -0,0 +1,127 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func TestPagerDutyNotifierNotify(t *testing.T) {
+	srv, reqs := recordingServer(t)
+	secrets := testSecrets(t)
+
+	mon := storage.Monitor{ID: 7, Name: "api", Url: "https://api.example.com"}
+	check := &storage.MonitorCheck{
+		StatusCode:     pgtype.Int4{Int32: 503, Valid: true},
+		ErrorMessage:   pgtype.Text{String: "service unavailable", Valid: true},
+		ResponseTimeMs: pgtype.Int4{Int32: 120, Valid: true},
+		CheckedAt:      pgtype.Timestamp{Time: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name    string
+		channel storage.AlertChannel
+		event   Event
+		want    pagerDutyEvent
+	}{
+		{
+			name: "trigger",
+			channel: storage.AlertChannel{
+				Config: []byte(`{"severity":"warning"}`),
+				Secret: seal(t, secrets, "sealed-routing-key"),
+			},
+			event: Event{Type: EventDown, Monitor: mon, Check: check},
+			want: pagerDutyEvent{
+				RoutingKey:  "sealed-routing-key",
+				EventAction: "trigger",
+				DedupKey:    "monitor-bee-monitor-7",
+				Client:      "monitor-bee",
+				ClientURL:   "https://app.example.com/monitors/7",
+				Links:       []pagerDutyLink{{Href: "https://app.example.com/monitors/7", Text: "View monitor"}},
+				Payload: &pagerDutyPayload{
+					Summary:   "api is down: service unavailable",
+					Source:    "https://api.example.com",
+					Severity:  "warning",
+					Timestamp: "2026-05-01T12:00:00Z",
+					CustomDetails: map[string]string{
+						"monitor":          "api",
+						"url":              "https://api.example.com",
+						"link":             "https://app.example.com/monitors/7",
+						"status_code":      "503",
+						"error_message":    "service unavailable",
+						"response_time_ms": "120",
+						"checked_at":       "2026-05-01T12:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "trigger defaults to critical",
+			channel: storage.AlertChannel{
+				Config: []byte(`{}`),
+				Secret: seal(t, secrets, "sealed-routing-key"),
+			},
+			event: Event{Type: EventDown, Monitor: storage.Monitor{ID: 8, Name: "cron"}},
+			want: pagerDutyEvent{
+				RoutingKey:  "sealed-routing-key",
+				EventAction: "trigger",
+				DedupKey:    "monitor-bee-monitor-8",
+				Client:      "monitor-bee",
+				ClientURL:   "https://app.example.com/monitors/8",
+				Links:       []pagerDutyLink{{Href: "https://app.example.com/monitors/8", Text: "View monitor"}},
+				Payload: &pagerDutyPayload{
+					Summary:  "cron is down",
+					Source:   "cron",
+					Severity: "critical",
+					CustomDetails: map[string]string{
+						"monitor": "cron",
+						"url":     "",
+						"link":    "https://app.example.com/monitors/8",
+					},
+				},
+			},
+		},
+		{
+			name:    "resolve",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, "sealed-routing-key")},
+			event:   Event{Type: EventUp, Monitor: mon, Check: check},
+			want: pagerDutyEvent{
+				RoutingKey:  "sealed-routing-key",
+				EventAction: "resolve",
+				DedupKey:    "monitor-bee-monitor-7",
+			},
+		},
+	}
+
+	n := NewPagerDutyNotifier(secrets, srv.URL+"/", "https://app.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reqs = nil
+
+			if err := n.Notify(context.Background(), tt.channel, tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*reqs))
+			}
+			req := (*reqs)[0]
+			if req.Path != "/v2/enqueue" {
+				t.Errorf("posted to %s, want /v2/enqueue", req.Path)
+			}
+
+			var got pagerDutyEvent
+			if err := json.Unmarshal(req.Body, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("event = %s\nwant %+v", req.Body, tt.want)
+			}
+		})
+	}
+}
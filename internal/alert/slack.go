@@ -0,0 +1,153 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Slack rejects block text longer than these.
+const (
+	slackMaxHeaderChars  = 150
+	slackMaxSectionChars = 3000
+)
+
+// SlackConfig is the config of a Slack channel.
+type SlackConfig struct {
+	// WebhookURL is a Slack incoming webhook. It is stored sealed, as the
+	// channel's secret.
+	WebhookURL string `json:"webhook_url"`
+}
+
+// SlackNotifier posts Block Kit messages to a Slack incoming webhook.
+type SlackNotifier struct {
+	client  *http.Client
+	secrets *secret.Box
+	appURL  string
+}
+
+func NewSlackNotifier(secrets *secret.Box, appURL string) *SlackNotifier {
+	return &SlackNotifier{
+		client:  &http.Client{Timeout: notifyTimeout},
+		secrets: secrets,
+		appURL:  appURL,
+	}
+}
+
+func (n *SlackNotifier) Validate(raw json.RawMessage) error {
+	var cfg SlackConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return err
+	}
+	return validateHTTPSURL("webhook_url", cfg.WebhookURL)
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	webhookURL, err := credential(n.secrets, channel)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(slackPayload(newMessage(event, n.appURL)))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	return postJSON(ctx, n.client, webhookURL, nil, body)
+}
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Fields   []slackText    `json:"fields,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackElement struct {
+	Type  string     `json:"type"`
+	Text  *slackText `json:"text,omitempty"`
+	URL   string     `json:"url,omitempty"`
+	Style string     `json:"style,omitempty"`
+}
+
+// slackPayload renders msg as a Block Kit message. Text is the fallback
+// shown in notifications.
+func slackPayload(msg message) any {
+	icon := ":large_green_circle:"
+	if msg.Down() {
+		icon = ":red_circle:"
+	}
+
+	fields := []slackText{
+		{Type: "mrkdwn", Text: "*Monitor*\n" + slackEscape(msg.MonitorName)},
+	}
+	// Heartbeat monitors have no URL
+	if msg.MonitorURL != "" {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*URL*\n" + slackEscape(msg.MonitorURL)})
+	}
+	if msg.StatusCode != 0 {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*Status code*\n%d", msg.StatusCode)})
+	}
+	if msg.ResponseTimeMs != 0 {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*Response time*\n%d ms", msg.ResponseTimeMs)})
+	}
+
+	blocks := []slackBlock{
+		{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncate(icon+" "+msg.Title, slackMaxHeaderChars), Emoji: true},
+		},
+		{Type: "section", Fields: fields},
+	}
+
+	if msg.ErrorMessage != "" {
+		// The heading and code block cost 14 characters of the section
+		text := "*Error*\n```" + truncate(slackEscape(msg.ErrorMessage), slackMaxSectionChars-14) + "```"
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: text},
+		})
+	}
+
+	button := slackElement{
+		Type: "button",
+		Text: &slackText{Type: "plain_text", Text: "View monitor"},
+		URL:  msg.Link,
+	}
+	if msg.Down() {
+		button.Style = "danger"
+	}
+	blocks = append(blocks, slackBlock{Type: "actions", Elements: []slackElement{button}})
+
+	return map[string]any{
+		"text":   slackEscape(msg.Title),
+		"blocks": blocks,
+	}
+}
+
+// slackEscape escapes the characters Slack treats as markup in mrkdwn text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// truncate shortens s to at most n characters, marking the cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+var _ Notifier = (*SlackNotifier)(nil)
@@ -0,0 +1,134 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// recordedRequest is a request received by a recordingServer.
+type recordedRequest struct {
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// recordingServer records each request it receives, answering 204.
+func recordingServer(t *testing.T) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+
+	var reqs []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		reqs = append(reqs, recordedRequest{Path: r.URL.RequestURI(), Header: r.Header, Body: body})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs
+}
+
+func testSecrets(t *testing.T) *secret.Box {
+	t.Helper()
+
+	secrets, err := secret.New("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secrets
+}
+
+func seal(t *testing.T, secrets *secret.Box, plain string) []byte {
+	t.Helper()
+
+	sealed, err := secrets.Seal([]byte(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealed
+}
+
+func TestSlackNotifierNotify(t *testing.T) {
+	srv, reqs := recordingServer(t)
+	secrets := testSecrets(t)
+
+	tests := []struct {
+		name    string
+		channel storage.AlertChannel
+		event   string
+		path    string
+		header  string
+	}{
+		{
+			name:    "sealed webhook url",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, srv.URL+"/sealed")},
+			event:   EventDown,
+			path:    "/sealed",
+			header:  ":red_circle: api is down",
+		},
+		{
+			name:    "recovery",
+			channel: storage.AlertChannel{Config: []byte(`{}`), Secret: seal(t, secrets, srv.URL+"/sealed")},
+			event:   EventUp,
+			path:    "/sealed",
+			header:  ":large_green_circle: api is back up",
+		},
+	}
+
+	n := NewSlackNotifier(secrets, "https://app.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reqs = nil
+
+			err := n.Notify(context.Background(), tt.channel, Event{
+				Type:    tt.event,
+				Monitor: storage.Monitor{ID: 7, Name: "api", Url: "https://api.example.com"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(*reqs))
+			}
+			req := (*reqs)[0]
+			if req.Path != tt.path {
+				t.Errorf("posted to %s, want %s", req.Path, tt.path)
+			}
+
+			var payload struct {
+				Text   string `json:"text"`
+				Blocks []struct {
+					Type string `json:"type"`
+					Text *struct {
+						Text string `json:"text"`
+					} `json:"text"`
+				} `json:"blocks"`
+			}
+			if err := json.Unmarshal(req.Body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if len(payload.Blocks) == 0 || payload.Blocks[0].Type != "header" || payload.Blocks[0].Text.Text != tt.header {
+				t.Errorf("payload %s does not start with header %q", req.Body, tt.header)
+			}
+		})
+	}
+}
+
+func TestSlackNotifierNotifyWithoutKey(t *testing.T) {
+	n := NewSlackNotifier(nil, "https://app.example.com")
+
+	err := n.Notify(context.Background(), storage.AlertChannel{Config: []byte(`{}`), Secret: []byte("sealed")}, Event{Type: EventDown})
+	if err == nil || !strings.Contains(err.Error(), secret.ErrNoKey.Error()) {
+		t.Errorf("Notify() error = %v, want %v", err, secret.ErrNoKey)
+	}
+}
@@ -0,0 +1,176 @@
+package alert
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Webhook request headers. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of the timestamp, a dot and the body, keyed with the
+// channel's secret. Signing the timestamp lets receivers reject replays of
+// old requests.
+const (
+	HeaderSignature = "X-Monitor-Bee-Signature"
+	HeaderTimestamp = "X-Monitor-Bee-Timestamp"
+	HeaderEvent     = "X-Monitor-Bee-Event"
+	HeaderDelivery  = "X-Monitor-Bee-Delivery"
+)
+
+// webhookSecretPrefix marks webhook signing secrets, so a leaked one is
+// recognisable.
+const webhookSecretPrefix = "whsec_"
+
+// WebhookConfig is the config of a webhook channel.
+type WebhookConfig struct {
+	URL string `json:"url"`
+	// Headers are added to every request. They cannot replace the
+	// signature headers.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	DeliveryID int64  `json:"delivery_id"`
+	Event      string `json:"event"`
+	// Monitor and Check are rendered as the API returns them, less the
+	// monitor's credentials.
+	Monitor any    `json:"monitor"`
+	Check   any    `json:"check"`
+	SentAt  string `json:"sent_at"`
+}
+
+// RenderFunc returns the representations of a monitor and the check
+// that changed its state. check may be nil.
+type RenderFunc func(mon storage.Monitor, check *storage.MonitorCheck) (any, any, error)
+
+// WebhookNotifier posts signed events to a URL.
+type WebhookNotifier struct {
+	client  *http.Client
+	secrets *secret.Box
+	render  RenderFunc
+}
+
+func NewWebhookNotifier(secrets *secret.Box, render RenderFunc) *WebhookNotifier {
+	return &WebhookNotifier{
+		client:  &http.Client{Timeout: notifyTimeout},
+		secrets: secrets,
+		render:  render,
+	}
+}
+
+// NewWebhookSecret returns a random signing secret for a webhook channel.
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(key []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a webhook request,
+// rejecting requests signed more than tolerance before now.
+func Verify(key []byte, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return errors.New("timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(key, ts, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func parseWebhookConfig(raw json.RawMessage) (WebhookConfig, error) {
+	var cfg WebhookConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (n *WebhookNotifier) Validate(raw json.RawMessage) error {
+	cfg, err := parseWebhookConfig(raw)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	for name := range cfg.Headers {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Monitor-Bee-") {
+			return fmt.Errorf("header %s is reserved", name)
+		}
+	}
+	return nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, channel storage.AlertChannel, event Event) error {
+	cfg, err := parseWebhookConfig(channel.Config)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	key, err := n.secrets.Open(channel.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to open signing secret: %w", err)
+	}
+
+	payload := WebhookPayload{
+		DeliveryID: event.DeliveryID,
+		Event:      "monitor." + event.Type,
+	}
+	payload.Monitor, payload.Check, err = n.render(event.Monitor, event.Check)
+	if err != nil {
+		return fmt.Errorf("failed to render payload: %w", err)
+	}
+
+	now := time.Now()
+	payload.SentAt = now.UTC().Format(time.RFC3339)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	header := make(http.Header)
+	for name, value := range cfg.Headers {
+		header.Set(name, value)
+	}
+	header.Set(HeaderEvent, payload.Event)
+	header.Set(HeaderDelivery, strconv.FormatInt(event.DeliveryID, 10))
+	header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	header.Set(HeaderSignature, Sign(key, now.Unix(), body))
+
+	return postJSON(ctx, n.client, cfg.URL, header, body)
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
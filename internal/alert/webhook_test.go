@@ -0,0 +1,67 @@
+package alert
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("whsec_test")
+	body := []byte(`{"event":"down"}`)
+	now := time.Unix(1700000000, 0)
+	ts := now.Unix()
+
+	tests := []struct {
+		name      string
+		key       []byte
+		signature string
+		timestamp string
+		body      []byte
+		wantErr   string
+	}{
+		{"valid", key, Sign(key, ts, body), strconv.FormatInt(ts, 10), body, ""},
+		{"signed within tolerance", key, Sign(key, ts-299, body), strconv.FormatInt(ts-299, 10), body, ""},
+		{"wrong key", []byte("whsec_other"), Sign(key, ts, body), strconv.FormatInt(ts, 10), body, "signature mismatch"},
+		{"tampered body", key, Sign(key, ts, body), strconv.FormatInt(ts, 10), []byte(`{"event":"up"}`), "signature mismatch"},
+		{"replayed timestamp", key, Sign(key, ts, body), strconv.FormatInt(ts+1, 10), body, "signature mismatch"},
+		{"missing prefix", key, strings.TrimPrefix(Sign(key, ts, body), "sha256="), strconv.FormatInt(ts, 10), body, "signature mismatch"},
+		{"invalid timestamp", key, Sign(key, ts, body), "yesterday", body, "invalid timestamp"},
+		{"too old", key, Sign(key, ts-301, body), strconv.FormatInt(ts-301, 10), body, "timestamp outside tolerance"},
+		{"too far ahead", key, Sign(key, ts+301, body), strconv.FormatInt(ts+301, 10), body, "timestamp outside tolerance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.key, tt.signature, tt.timestamp, tt.body, 5*time.Minute, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewWebhookSecret(t *testing.T) {
+	a, err := NewWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(a, webhookSecretPrefix) {
+		t.Errorf("NewWebhookSecret() = %q, want prefix %q", a, webhookSecretPrefix)
+	}
+	if a == b {
+		t.Errorf("NewWebhookSecret() returned %q twice", a)
+	}
+}
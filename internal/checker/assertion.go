@@ -0,0 +1,274 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Assertion types.
+const (
+	AssertContains     = "contains"      // body contains Value
+	AssertNotContains  = "not_contains"  // body does not contain Value
+	AssertRegex        = "regex"         // body matches the regular expression Value
+	AssertJSONPath     = "json_path"     // JSON value at Property compared to Value with Operator
+	AssertHeader       = "header"        // header Property equals Value
+	AssertResponseTime = "response_time" // response time is under Value milliseconds
+)
+
+// Assertion operators for json_path assertions.
+const (
+	OpEquals      = "equals"
+	OpGreaterThan = "greater_than"
+)
+
+// Assertion is a rule a response must satisfy for a check to succeed.
+type Assertion struct {
+	Type     string `json:"type"`
+	Property string `json:"property,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value"`
+}
+
+// ValidateAssertions reports the first assertion that can never be evaluated,
+// such as an invalid regular expression or a missing JSONPath.
+func ValidateAssertions(assertions []Assertion) error {
+	for i, a := range assertions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) validate() error {
+	switch a.Type {
+	case AssertContains, AssertNotContains:
+		if _, ok := a.Value.(string); !ok {
+			return errors.New("value must be a string")
+		}
+	case AssertRegex:
+		pattern, ok := a.Value.(string)
+		if !ok {
+			return errors.New("value must be a string")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	case AssertJSONPath:
+		if _, err := parseJSONPath(a.Property); err != nil {
+			return err
+		}
+		switch a.Operator {
+		case OpEquals:
+		case OpGreaterThan:
+			if _, ok := a.Value.(float64); !ok {
+				return errors.New("value must be a number")
+			}
+		default:
+			return fmt.Errorf("operator must be %q or %q", OpEquals, OpGreaterThan)
+		}
+	case AssertHeader:
+		if a.Property == "" {
+			return errors.New("property must name a header")
+		}
+		if _, ok := a.Value.(string); !ok {
+			return errors.New("value must be a string")
+		}
+	case AssertResponseTime:
+		if ms, ok := a.Value.(float64); !ok || ms <= 0 {
+			return errors.New("value must be a positive number of milliseconds")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", a.Type)
+	}
+	return nil
+}
+
+// needsBody reports whether any assertion inspects the response body.
+func needsBody(assertions []Assertion) bool {
+	for _, a := range assertions {
+		switch a.Type {
+		case AssertContains, AssertNotContains, AssertRegex, AssertJSONPath:
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate checks every assertion against the response and returns a
+// description of the first one that fails.
+func evaluate(assertions []Assertion, body []byte, header http.Header, elapsed time.Duration) error {
+	var doc any
+	var docErr error
+	parsed := false
+
+	for i, a := range assertions {
+		var err error
+		switch a.Type {
+		case AssertContains:
+			if !bytes.Contains(body, []byte(a.Value.(string))) {
+				err = fmt.Errorf("body does not contain %q", a.Value)
+			}
+		case AssertNotContains:
+			if bytes.Contains(body, []byte(a.Value.(string))) {
+				err = fmt.Errorf("body contains %q", a.Value)
+			}
+		case AssertRegex:
+			re, compileErr := regexp.Compile(a.Value.(string))
+			if compileErr != nil {
+				err = compileErr
+			} else if !re.Match(body) {
+				err = fmt.Errorf("body does not match %q", a.Value)
+			}
+		case AssertJSONPath:
+			if !parsed {
+				docErr = json.Unmarshal(body, &doc)
+				parsed = true
+			}
+			if docErr != nil {
+				err = fmt.Errorf("body is not valid JSON: %w", docErr)
+			} else {
+				err = a.compareJSON(doc)
+			}
+		case AssertHeader:
+			if got := header.Get(a.Property); got != a.Value.(string) {
+				err = fmt.Errorf("header %s is %q, expected %q", a.Property, got, a.Value)
+			}
+		case AssertResponseTime:
+			limit := time.Duration(a.Value.(float64) * float64(time.Millisecond))
+			if elapsed >= limit {
+				err = fmt.Errorf("response time %dms is not under %vms", elapsed.Milliseconds(), a.Value)
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("assertion %d (%s) failed: %w", i+1, a.Type, err)
+		}
+	}
+
+	return nil
+}
+
+func (a Assertion) compareJSON(doc any) error {
+	path, err := parseJSONPath(a.Property)
+	if err != nil {
+		return err
+	}
+
+	got, ok := lookupJSONPath(doc, path)
+	if !ok {
+		return fmt.Errorf("%s not found", a.Property)
+	}
+
+	switch a.Operator {
+	case OpEquals:
+		if !jsonEqual(got, a.Value) {
+			return fmt.Errorf("%s is %s, expected %s", a.Property, jsonString(got), jsonString(a.Value))
+		}
+	case OpGreaterThan:
+		n, ok := got.(float64)
+		if !ok {
+			return fmt.Errorf("%s is %s, not a number", a.Property, jsonString(got))
+		}
+		if n <= a.Value.(float64) {
+			return fmt.Errorf("%s is %s, expected greater than %s", a.Property, jsonString(got), jsonString(a.Value))
+		}
+	}
+	return nil
+}
+
+func jsonEqual(a, b any) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// parseJSONPath parses the subset of JSONPath made of field and index
+// selectors, e.g. $.data.items[0].name or $['key'].
+func parseJSONPath(path string) ([]any, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %q must start with $", path)
+	}
+
+	var segments []any
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("json path %q has an empty field name", path)
+			}
+			segments = append(segments, name)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("json path %q has an unclosed bracket", path)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, inner[1:len(inner)-1])
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("json path %q has an invalid index %q", path, inner)
+				}
+				segments = append(segments, index)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("json path %q is invalid at %q", path, rest)
+		}
+	}
+
+	return segments, nil
+}
+
+func lookupJSONPath(doc any, path []any) (any, bool) {
+	cur := doc
+	for _, seg := range path {
+		switch key := seg.(type) {
+		case string:
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := cur.([]any)
+			if !ok || key >= len(arr) {
+				return nil, false
+			}
+			cur = arr[key]
+		}
+	}
+	return cur, true
+}
@@ -0,0 +1,148 @@
+package checker
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []any
+		wantErr bool
+	}{
+		{"$", nil, false},
+		{"$.status", []any{"status"}, false},
+		{"$.data.items[0].name", []any{"data", "items", 0, "name"}, false},
+		{"$['content-type']", []any{"content-type"}, false},
+		{`$["a.b"][2]`, []any{"a.b", 2}, false},
+		{"status", nil, true},
+		{"$.", nil, true},
+		{"$.items[0", nil, true},
+		{"$.items[-1]", nil, true},
+		{"$.items[x]", nil, true},
+		{"$items", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseJSONPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseJSONPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAssertions(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion Assertion
+		wantErr   bool
+	}{
+		{"contains", Assertion{Type: AssertContains, Value: "ok"}, false},
+		{"contains non-string", Assertion{Type: AssertContains, Value: 1.0}, true},
+		{"regex", Assertion{Type: AssertRegex, Value: `^\{"status":`}, false},
+		{"invalid regex", Assertion{Type: AssertRegex, Value: "("}, true},
+		{"json path equals", Assertion{Type: AssertJSONPath, Property: "$.ok", Operator: OpEquals, Value: true}, false},
+		{"json path greater than", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpGreaterThan, Value: 3.0}, false},
+		{"json path greater than string", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpGreaterThan, Value: "3"}, true},
+		{"json path unknown operator", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: "less_than", Value: 3.0}, true},
+		{"json path invalid path", Assertion{Type: AssertJSONPath, Property: "count", Operator: OpEquals, Value: 3.0}, true},
+		{"header", Assertion{Type: AssertHeader, Property: "Content-Type", Value: "application/json"}, false},
+		{"header without name", Assertion{Type: AssertHeader, Value: "application/json"}, true},
+		{"response time", Assertion{Type: AssertResponseTime, Value: 500.0}, false},
+		{"response time zero", Assertion{Type: AssertResponseTime, Value: 0.0}, true},
+		{"unknown type", Assertion{Type: "status_code", Value: 200.0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAssertions([]Assertion{tt.assertion})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAssertions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	body := []byte(`{"status":"ok","count":5,"data":{"items":[{"name":"first"},{"name":"second"}]},"tags":["a","b"]}`)
+	header := http.Header{"Content-Type": {"application/json"}}
+	elapsed := 120 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		body      []byte
+		wantErr   bool
+	}{
+		{"contains", Assertion{Type: AssertContains, Value: `"status":"ok"`}, body, false},
+		{"contains missing", Assertion{Type: AssertContains, Value: "error"}, body, true},
+		{"not contains", Assertion{Type: AssertNotContains, Value: "error"}, body, false},
+		{"not contains present", Assertion{Type: AssertNotContains, Value: "second"}, body, true},
+		{"regex", Assertion{Type: AssertRegex, Value: `"count":\d+`}, body, false},
+		{"regex no match", Assertion{Type: AssertRegex, Value: `"count":"\d+"`}, body, true},
+		{"json equals string", Assertion{Type: AssertJSONPath, Property: "$.status", Operator: OpEquals, Value: "ok"}, body, false},
+		{"json equals number", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpEquals, Value: 5.0}, body, false},
+		{"json equals array", Assertion{Type: AssertJSONPath, Property: "$.tags", Operator: OpEquals, Value: []any{"a", "b"}}, body, false},
+		{"json equals nested", Assertion{Type: AssertJSONPath, Property: "$.data.items[1].name", Operator: OpEquals, Value: "second"}, body, false},
+		{"json equals mismatch", Assertion{Type: AssertJSONPath, Property: "$.status", Operator: OpEquals, Value: "down"}, body, true},
+		{"json equals type mismatch", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpEquals, Value: "5"}, body, true},
+		{"json greater than", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpGreaterThan, Value: 4.0}, body, false},
+		{"json greater than equal", Assertion{Type: AssertJSONPath, Property: "$.count", Operator: OpGreaterThan, Value: 5.0}, body, true},
+		{"json greater than non-number", Assertion{Type: AssertJSONPath, Property: "$.status", Operator: OpGreaterThan, Value: 1.0}, body, true},
+		{"json missing field", Assertion{Type: AssertJSONPath, Property: "$.missing", Operator: OpEquals, Value: nil}, body, true},
+		{"json index out of range", Assertion{Type: AssertJSONPath, Property: "$.data.items[2]", Operator: OpEquals, Value: nil}, body, true},
+		{"json invalid body", Assertion{Type: AssertJSONPath, Property: "$.status", Operator: OpEquals, Value: "ok"}, []byte("<html>"), true},
+		{"header", Assertion{Type: AssertHeader, Property: "content-type", Value: "application/json"}, body, false},
+		{"header mismatch", Assertion{Type: AssertHeader, Property: "Content-Type", Value: "text/html"}, body, true},
+		{"response time", Assertion{Type: AssertResponseTime, Value: 200.0}, body, false},
+		{"response time exceeded", Assertion{Type: AssertResponseTime, Value: 120.0}, body, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := evaluate([]Assertion{tt.assertion}, tt.body, header, elapsed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateReportsFirstFailure(t *testing.T) {
+	assertions := []Assertion{
+		{Type: AssertContains, Value: "ok"},
+		{Type: AssertNotContains, Value: "ok"},
+		{Type: AssertContains, Value: "missing"},
+	}
+
+	err := evaluate(assertions, []byte("ok"), nil, 0)
+	if err == nil || err.Error() != `assertion 2 (not_contains) failed: body contains "ok"` {
+		t.Errorf("evaluate() error = %v, want the second assertion to fail", err)
+	}
+}
+
+func TestNeedsBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions []Assertion
+		want       bool
+	}{
+		{"none", nil, false},
+		{"header and time", []Assertion{{Type: AssertHeader}, {Type: AssertResponseTime}}, false},
+		{"contains", []Assertion{{Type: AssertHeader}, {Type: AssertContains}}, true},
+		{"json path", []Assertion{{Type: AssertJSONPath}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := needsBody(tt.assertions); got != tt.want {
+			t.Errorf("needsBody(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
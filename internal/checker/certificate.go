@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"crypto/tls"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Certificate expiry actions, taken when a certificate expires within the
+// monitor's threshold.
+const (
+	CertExpiryWarn = "warn" // the check succeeds with a warning
+	CertExpiryFail = "fail" // the check fails
+)
+
+// Certificate describes one certificate of the chain a server presented.
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	SANs      []string  `json:"sans"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// DaysRemaining returns the number of whole days until the certificate
+// expires. It is negative once the certificate has expired.
+func (c Certificate) DaysRemaining(now time.Time) int {
+	remaining := c.NotAfter.Sub(now)
+	return int(math.Floor(remaining.Hours() / 24))
+}
+
+// certificateChain returns the peer certificates of a TLS connection, leaf
+// first.
+func certificateChain(state *tls.ConnectionState) []Certificate {
+	if state == nil {
+		return nil
+	}
+
+	chain := make([]Certificate, 0, len(state.PeerCertificates))
+	for _, cert := range state.PeerCertificates {
+		sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+		sans = append(sans, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+
+		chain = append(chain, Certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			SANs:      sans,
+			NotBefore: cert.NotBefore.UTC(),
+			NotAfter:  cert.NotAfter.UTC(),
+		})
+	}
+	return chain
+}
+
+// certificateExpiry describes the leaf certificate's expiry when it falls
+// within thresholdDays, or returns an empty string.
+func certificateExpiry(chain []Certificate, thresholdDays int, now time.Time) string {
+	if len(chain) == 0 || thresholdDays <= 0 {
+		return ""
+	}
+
+	days := chain[0].DaysRemaining(now)
+	if days >= thresholdDays {
+		return ""
+	}
+	if days < 0 {
+		return fmt.Sprintf("Certificate expired on %s", chain[0].NotAfter.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("Certificate expires in %d days on %s", days, chain[0].NotAfter.Format(time.DateOnly))
+}
@@ -0,0 +1,33 @@
+package checker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertificateDaysRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		want     int
+	}{
+		{"thirty days left", now.Add(30 * 24 * time.Hour), 30},
+		{"part of a day left", now.Add(23 * time.Hour), 0},
+		{"a day and a half left", now.Add(36 * time.Hour), 1},
+		{"expires now", now, 0},
+		{"expired an hour ago", now.Add(-time.Hour), -1},
+		{"expired exactly a day ago", now.Add(-24 * time.Hour), -1},
+		{"expired a day and a half ago", now.Add(-36 * time.Hour), -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Certificate{NotAfter: tt.notAfter}
+			if got := c.DaysRemaining(now); got != tt.want {
+				t.Errorf("DaysRemaining() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+type CheckResult struct {
+	Status         string        // "success" or "failed"
+	ResponseTimeMs int           // How long it took
+	StatusCode     int           // HTTP status code
+	ErrorMessage   string        // If failed, what went wrong
+	Timings        *Timings      // Phase breakdown, when a response was received
+	Certificates   []Certificate // TLS peer chain, leaf first, for HTTPS checks
+	Warning        string        // Problem that did not fail the check
+	Details        any           // Type-specific outcome, stored as JSON
+}
+
+// transportIdleTimeout is how long a shared transport is kept after its last
+// check. Its pooled connections close after IdleConnTimeout anyway, so
+// little is lost by dropping it, and settings no monitor uses any more,
+// such as an old proxy URL, do not hold on to transports.
+const transportIdleTimeout = 5 * time.Minute
+
+// Checker runs monitor checks. It is long-lived and safe for concurrent use:
+// HTTP transports are shared between checks with the same TLS and proxy
+// settings so connections to a target are pooled and reused.
+type Checker struct {
+	mu         sync.Mutex
+	transports map[transportKey]*sharedTransport
+	secrets    *secret.Box
+}
+
+type transportKey struct {
+	skipTLSVerify bool
+	proxyURL      string
+}
+
+// sharedTransport is a cached transport with the number of checks using it.
+type sharedTransport struct {
+	tr       *http.Transport
+	inUse    int
+	lastUsed time.Time
+}
+
+func New(secrets *secret.Box) *Checker {
+	return &Checker{
+		transports: make(map[transportKey]*sharedTransport),
+		secrets:    secrets,
+	}
+}
+
+// Monitor types.
+const (
+	TypeHTTP      = "http"
+	TypeTCP       = "tcp"
+	TypeDNS       = "dns"
+	TypeGRPC      = "grpc"
+	TypeWebSocket = "websocket"
+	TypeMultiStep = "multistep"
+	TypePostgres  = "postgres"
+	TypeMySQL     = "mysql"
+
+	// TypeHeartbeat monitors are pinged by the job they watch instead of
+	// being polled.
+	TypeHeartbeat = "heartbeat"
+)
+
+// Check runs a single check for mon.
+func (c *Checker) Check(ctx context.Context, mon storage.Monitor) CheckResult {
+	switch mon.Type {
+	case TypeHTTP, "":
+		return c.checkHTTP(ctx, mon)
+	case TypeTCP:
+		return c.checkTCP(ctx, mon)
+	case TypeDNS:
+		return c.checkDNS(ctx, mon)
+	case TypeGRPC:
+		return c.checkGRPC(ctx, mon)
+	case TypeWebSocket:
+		return c.checkWebSocket(ctx, mon)
+	case TypeMultiStep:
+		return c.checkMultiStep(ctx, mon)
+	case TypePostgres, TypeMySQL:
+		return c.checkDatabase(ctx, mon)
+	case TypeHeartbeat:
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Heartbeat monitors are not polled",
+		}
+	default:
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Unsupported monitor type: " + mon.Type,
+		}
+	}
+}
+
+// Close releases idle connections held by the shared transports.
+func (c *Checker) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, st := range c.transports {
+		st.tr.CloseIdleConnections()
+		delete(c.transports, key)
+	}
+}
+
+// transport returns the transport to use for mon and a function to call once
+// the check is done. Monitors that force a fresh connection get a one-off
+// transport without keep-alives, so every check pays the full connect cost.
+func (c *Checker) transport(mon storage.Monitor) (*http.Transport, func(), error) {
+	key := transportKey{
+		skipTLSVerify: mon.SkipTlsVerify,
+		proxyURL:      mon.ProxyUrl.String,
+	}
+
+	if mon.FreshConnection {
+		tr, err := newTransport(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		tr.DisableKeepAlives = true
+		return tr, tr.CloseIdleConnections, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictIdle(time.Now())
+
+	st, ok := c.transports[key]
+	if !ok {
+		tr, err := newTransport(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		st = &sharedTransport{tr: tr}
+		c.transports[key] = st
+	}
+	st.inUse++
+
+	release := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		st.inUse--
+		st.lastUsed = time.Now()
+	}
+	return st.tr, release, nil
+}
+
+// evictIdle drops the shared transports no check has used for
+// transportIdleTimeout. c.mu must be held.
+func (c *Checker) evictIdle(now time.Time) {
+	for key, st := range c.transports {
+		if st.inUse == 0 && now.Sub(st.lastUsed) > transportIdleTimeout {
+			st.tr.CloseIdleConnections()
+			delete(c.transports, key)
+		}
+	}
+}
+
+func newTransport(key transportKey) (*http.Transport, error) {
+	tr := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: key.skipTLSVerify,
+		},
+	}
+
+	if key.proxyURL != "" {
+		proxy, err := url.Parse(key.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		tr.Proxy = http.ProxyURL(proxy)
+	}
+
+	return tr, nil
+}
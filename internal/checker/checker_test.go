@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func proxiedMonitor(proxyURL string) storage.Monitor {
+	return storage.Monitor{ProxyUrl: pgtype.Text{String: proxyURL, Valid: true}}
+}
+
+func TestCheckerSharesTransports(t *testing.T) {
+	c := New(nil)
+	defer c.Close()
+
+	a, releaseA, err := c.transport(proxiedMonitor("http://proxy-a:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, releaseB, err := c.transport(proxiedMonitor("http://proxy-a:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseA()
+	releaseB()
+
+	if a != b {
+		t.Error("monitors with the same settings got different transports")
+	}
+}
+
+func TestCheckerEvictsIdleTransports(t *testing.T) {
+	c := New(nil)
+	defer c.Close()
+
+	_, releaseOld, err := c.transport(proxiedMonitor("http://old-proxy:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseOld()
+
+	_, releaseBusy, err := c.transport(proxiedMonitor("http://busy-proxy:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer releaseBusy()
+
+	c.mu.Lock()
+	for _, st := range c.transports {
+		st.lastUsed = time.Now().Add(-2 * transportIdleTimeout)
+	}
+	c.mu.Unlock()
+
+	_, releaseNew, err := c.transport(proxiedMonitor("http://new-proxy:3128"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer releaseNew()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tests := []struct {
+		proxyURL string
+		cached   bool
+	}{
+		{"http://old-proxy:3128", false},
+		{"http://busy-proxy:3128", true},
+		{"http://new-proxy:3128", true},
+	}
+	for _, tt := range tests {
+		_, ok := c.transports[transportKey{proxyURL: tt.proxyURL}]
+		if ok != tt.cached {
+			t.Errorf("%s cached = %v, want %v", tt.proxyURL, ok, tt.cached)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package checker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// defaultDatabaseQuery is run when a database monitor does not set a query.
+const defaultDatabaseQuery = "SELECT 1"
+
+// maxValueDetailBytes caps how much of a returned value is kept in the check
+// details.
+const maxValueDetailBytes = 256
+
+// DatabaseSettings are the type-specific settings of a Postgres or MySQL
+// monitor. The monitor's url holds the host:port of the server, used for
+// display; the connection itself uses the DSN stored encrypted in its secret.
+type DatabaseSettings struct {
+	// Query is run in a read-only transaction. Only the first column of the
+	// first row is read.
+	Query string `json:"query,omitempty"`
+	// Expect is the value the query must return, compared as text. NULL is
+	// compared as "NULL".
+	Expect *string `json:"expect,omitempty"`
+}
+
+// DatabaseDetails is the outcome of a database check, stored with the check
+// result.
+type DatabaseDetails struct {
+	ConnectMs int     `json:"connect_ms"`
+	QueryMs   *int    `json:"query_ms,omitempty"`
+	Value     *string `json:"value,omitempty"`
+}
+
+// ParseDatabaseSettings decodes the settings of a database monitor and fills
+// in the default query.
+func ParseDatabaseSettings(raw []byte) (DatabaseSettings, error) {
+	var settings DatabaseSettings
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return settings, err
+		}
+	}
+	if settings.Query == "" {
+		settings.Query = defaultDatabaseQuery
+	}
+	return settings, nil
+}
+
+// databaseDriver returns the database/sql driver for a monitor type.
+func databaseDriver(monitorType string) string {
+	if monitorType == TypeMySQL {
+		return "mysql"
+	}
+	return "pgx"
+}
+
+func (c *Checker) checkDatabase(ctx context.Context, mon storage.Monitor) CheckResult {
+	settings, err := ParseDatabaseSettings(mon.Settings)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Invalid settings: " + err.Error(),
+		}
+	}
+
+	if len(mon.Secret) == 0 {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "No DSN configured",
+		}
+	}
+
+	dsn, err := c.secrets.Open(mon.Secret)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Failed to decrypt DSN: " + err.Error(),
+		}
+	}
+
+	db, err := sql.Open(databaseDriver(mon.Type), string(dsn))
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Invalid DSN: " + err.Error(),
+		}
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+
+	if err := db.PingContext(ctx); err != nil {
+		return CheckResult{
+			Status:         "failed",
+			ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+			ErrorMessage:   "Connection failed: " + err.Error(),
+		}
+	}
+
+	details := DatabaseDetails{ConnectMs: int(time.Since(startTime).Milliseconds())}
+	result := CheckResult{Status: "success", Details: &details}
+
+	queryTime := time.Now()
+	value, err := queryValue(ctx, db, settings.Query)
+	queryMs := int(time.Since(queryTime).Milliseconds())
+	details.QueryMs = &queryMs
+	result.ResponseTimeMs = int(time.Since(startTime).Milliseconds())
+
+	if err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	shown := value[:min(len(value), maxValueDetailBytes)]
+	details.Value = &shown
+
+	if settings.Expect != nil && value != *settings.Expect {
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Unexpected value: got %q, expected %q", shown, *settings.Expect)
+	}
+
+	return result
+}
+
+// queryValue runs query in a read-only transaction, so a monitor cannot
+// change the database it watches, and returns the first column of the first
+// row as text.
+func queryValue(ctx context.Context, db *sql.DB, query string) (string, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("Failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("Query failed: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", fmt.Errorf("Query failed: %w", err)
+		}
+		return "", errors.New("Query returned no rows")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("Query failed: %w", err)
+	}
+	if len(columns) == 0 {
+		return "", errors.New("Query returned no columns")
+	}
+
+	// Only the first column is kept; the rest are scanned and discarded
+	dest := make([]any, len(columns))
+	var value any
+	dest[0] = &value
+	for i := 1; i < len(dest); i++ {
+		dest[i] = new(any)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", fmt.Errorf("Failed to read result: %w", err)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case []byte:
+		return string(v), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
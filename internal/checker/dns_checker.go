@@ -0,0 +1,234 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// DNS record types a DNS monitor can resolve.
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordMX    = "MX"
+	RecordTXT   = "TXT"
+	RecordNS    = "NS"
+)
+
+// DNS answer matching modes.
+const (
+	MatchExact    = "exact"    // the answers are exactly the expected values
+	MatchContains = "contains" // the answers include every expected value
+)
+
+// DNSSettings are the type-specific settings of a DNS monitor. The monitor's
+// url holds the name to resolve.
+type DNSSettings struct {
+	RecordType string `json:"record_type"`
+	// Resolver is the host:port of the DNS server to query. The system
+	// resolver is used when it is empty.
+	Resolver string   `json:"resolver,omitempty"`
+	Expected []string `json:"expected,omitempty"`
+	Match    string   `json:"match,omitempty"`
+}
+
+// DNSDetails is the outcome of a DNS check, stored with the check result.
+type DNSDetails struct {
+	RecordType string   `json:"record_type"`
+	Resolver   string   `json:"resolver,omitempty"`
+	Answers    []string `json:"answers"`
+}
+
+// Valid reports whether the settings describe a query that can be run.
+func (s DNSSettings) Valid() error {
+	switch s.RecordType {
+	case RecordA, RecordAAAA, RecordCNAME, RecordMX, RecordTXT, RecordNS:
+	default:
+		return errors.New("record_type must be one of A, AAAA, CNAME, MX, TXT or NS")
+	}
+
+	if s.Resolver != "" {
+		if _, _, err := net.SplitHostPort(s.Resolver); err != nil {
+			return errors.New("resolver must be a host:port")
+		}
+	}
+
+	switch s.Match {
+	case "", MatchExact, MatchContains:
+	default:
+		return fmt.Errorf("match must be %q or %q", MatchExact, MatchContains)
+	}
+
+	return nil
+}
+
+// ParseDNSSettings decodes and validates the settings of a DNS monitor.
+func ParseDNSSettings(raw []byte) (DNSSettings, error) {
+	var settings DNSSettings
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return settings, err
+		}
+	}
+	return settings, settings.Valid()
+}
+
+func (c *Checker) checkDNS(ctx context.Context, mon storage.Monitor) CheckResult {
+	settings, err := ParseDNSSettings(mon.Settings)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Invalid settings: " + err.Error(),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	resolver := net.DefaultResolver
+	if settings.Resolver != "" {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, settings.Resolver)
+			},
+		}
+	}
+
+	startTime := time.Now()
+	answers, err := lookup(ctx, resolver, settings.RecordType, mon.Url)
+	responseTime := int(time.Since(startTime).Milliseconds())
+
+	details := DNSDetails{
+		RecordType: settings.RecordType,
+		Resolver:   settings.Resolver,
+		Answers:    answers,
+	}
+
+	if err != nil {
+		return CheckResult{
+			Status:         "failed",
+			ResponseTimeMs: responseTime,
+			ErrorMessage:   "Lookup failed: " + err.Error(),
+			Details:        details,
+		}
+	}
+
+	result := CheckResult{
+		Status:         "success",
+		ResponseTimeMs: responseTime,
+		Details:        details,
+	}
+
+	if err := matchAnswers(answers, settings.Expected, settings.RecordType, settings.Match); err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = err.Error()
+	}
+
+	return result
+}
+
+// lookup resolves name and returns the answers in a normalised, sorted form.
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case RecordA, RecordAAAA:
+		network := "ip4"
+		if recordType == RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case RecordCNAME:
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case RecordMX:
+		mxs, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, strconv.Itoa(int(mx.Pref))+" "+mx.Host)
+		}
+	case RecordTXT:
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, txts...)
+	case RecordNS:
+		nss, err := resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			answers = append(answers, ns.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	for i, a := range answers {
+		answers[i] = normaliseAnswer(recordType, a)
+	}
+	slices.Sort(answers)
+	return slices.Compact(answers), nil
+}
+
+// normaliseAnswer makes addresses and host names comparable regardless of
+// case and the trailing dot of fully qualified names. TXT records are free
+// text, often case-sensitive tokens, so they are left as they are.
+func normaliseAnswer(recordType, answer string) string {
+	if recordType == RecordTXT {
+		return answer
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(answer)), ".")
+}
+
+func matchAnswers(answers, expected []string, recordType, mode string) error {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	want := make([]string, 0, len(expected))
+	for _, e := range expected {
+		want = append(want, normaliseAnswer(recordType, e))
+	}
+	slices.Sort(want)
+	want = slices.Compact(want)
+
+	var missing []string
+	for _, w := range want {
+		if !slices.Contains(answers, w) {
+			missing = append(missing, w)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Expected answers missing: %s (got %s)", strings.Join(missing, ", "), strings.Join(answers, ", "))
+	}
+
+	if mode != MatchContains && len(answers) != len(want) {
+		return fmt.Errorf("Unexpected answers: got %s, expected %s", strings.Join(answers, ", "), strings.Join(want, ", "))
+	}
+
+	return nil
+}
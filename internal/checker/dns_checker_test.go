@@ -0,0 +1,56 @@
+package checker
+
+import "testing"
+
+func TestNormaliseAnswer(t *testing.T) {
+	tests := []struct {
+		recordType string
+		answer     string
+		want       string
+	}{
+		{RecordA, " 93.184.216.34 ", "93.184.216.34"},
+		{RecordAAAA, "2606:2800:220:1:248:1893:25C8:1946", "2606:2800:220:1:248:1893:25c8:1946"},
+		{RecordCNAME, "Edge.Example.COM.", "edge.example.com"},
+		{RecordMX, "10 MX1.Example.com.", "10 mx1.example.com"},
+		{RecordNS, "NS1.Example.com.", "ns1.example.com"},
+		{RecordTXT, "google-site-verification=AbC123.", "google-site-verification=AbC123."},
+		{RecordTXT, "v=DKIM1; k=rsa; p=MIGfMA0G", "v=DKIM1; k=rsa; p=MIGfMA0G"},
+	}
+
+	for _, tt := range tests {
+		if got := normaliseAnswer(tt.recordType, tt.answer); got != tt.want {
+			t.Errorf("normaliseAnswer(%s, %q) = %q, want %q", tt.recordType, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAnswers(t *testing.T) {
+	tests := []struct {
+		name       string
+		recordType string
+		answers    []string
+		expected   []string
+		mode       string
+		wantErr    bool
+	}{
+		{"nothing expected", RecordA, []string{"1.1.1.1"}, nil, "", false},
+		{"exact match", RecordA, []string{"1.0.0.1", "1.1.1.1"}, []string{"1.1.1.1", "1.0.0.1"}, MatchExact, false},
+		{"exact is the default", RecordA, []string{"1.0.0.1", "1.1.1.1"}, []string{"1.1.1.1"}, "", true},
+		{"exact with extra answer", RecordA, []string{"1.0.0.1", "1.1.1.1"}, []string{"1.1.1.1"}, MatchExact, true},
+		{"contains with extra answer", RecordA, []string{"1.0.0.1", "1.1.1.1"}, []string{"1.1.1.1"}, MatchContains, false},
+		{"missing answer", RecordA, []string{"1.1.1.1"}, []string{"8.8.8.8"}, MatchContains, true},
+		{"duplicate expectations", RecordA, []string{"1.1.1.1"}, []string{"1.1.1.1", "1.1.1.1"}, MatchExact, false},
+		{"host name case and dot", RecordCNAME, []string{"edge.example.com"}, []string{"Edge.Example.com."}, MatchExact, false},
+		{"txt matches exactly", RecordTXT, []string{"token=AbC123"}, []string{"token=AbC123"}, MatchExact, false},
+		{"txt is case-sensitive", RecordTXT, []string{"token=AbC123"}, []string{"token=abc123"}, MatchExact, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := matchAnswers(tt.answers, tt.expected, tt.recordType, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("matchAnswers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hs := health.NewServer()
+	hs.SetServingStatus("up", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("down", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	// Nothing listens on a closed listener's address
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		addr    string
+		service string
+		status  string
+	}{
+		{"serving", lis.Addr().String(), "up", "success"},
+		{"not serving", lis.Addr().String(), "down", "failed"},
+		{"unknown service", lis.Addr().String(), "missing", "failed"},
+		{"nothing listening", closedAddr, "", "failed"},
+	}
+
+	c := New(nil)
+	defer c.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, _ := json.Marshal(GRPCSettings{Service: tt.service})
+			res := c.checkGRPC(context.Background(), storage.Monitor{
+				Url:            tt.addr,
+				TimeoutSeconds: 5,
+				Settings:       settings,
+			})
+			if res.Status != tt.status {
+				t.Errorf("status = %s (%s), want %s", res.Status, res.ErrorMessage, tt.status)
+			}
+		})
+	}
+}
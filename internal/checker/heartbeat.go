@@ -0,0 +1,17 @@
+package checker
+
+// Heartbeat events, recorded as the details of a heartbeat monitor's checks.
+const (
+	HeartbeatSuccess = "success" // the job reported success
+	HeartbeatFail    = "fail"    // the job reported failure
+	HeartbeatMissed  = "missed"  // no ping arrived within interval plus grace
+)
+
+// HeartbeatDetails describes a ping received from, or missed by, a heartbeat
+// monitor's job. Heartbeat monitors are never polled: their checks are
+// recorded when pings arrive or their deadline passes.
+type HeartbeatDetails struct {
+	Event      string `json:"event"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+}
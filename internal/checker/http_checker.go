@@ -1,8 +1,10 @@
 package checker
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -12,23 +14,25 @@ import (
 	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
 )
 
-type CheckResult struct {
-	Status         string // "success" or "failed"
-	ResponseTimeMs int    // How long it took
-	StatusCode     int    // HTTP status code
-	ErrorMessage   string // If failed, what went wrong
-}
+// maxBodyBytes caps how much of a response body is read. Reading it is part
+// of the measured response time and lets the connection go back to the pool.
+const maxBodyBytes = 1 << 20
 
-func HTTPMonitor(mon storage.Monitor) CheckResult {
-	timeout := time.Duration(mon.TimeoutSeconds) * time.Second
-	client := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false, // Validate SSL
-			},
-		},
+func (c *Checker) checkHTTP(ctx context.Context, mon storage.Monitor) CheckResult {
+	tr, release, err := c.transport(mon)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Failed to configure transport: " + err.Error(),
+		}
 	}
+	defer release()
+
+	client := &http.Client{Transport: tr}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
 	parsedUrl, err := url.Parse(mon.Url)
 	if err != nil {
 		return CheckResult{
@@ -37,12 +41,29 @@ func HTTPMonitor(mon storage.Monitor) CheckResult {
 		}
 	}
 
+	var assertions []Assertion
+	if len(mon.Assertions) > 0 {
+		if err := json.Unmarshal(mon.Assertions, &assertions); err != nil {
+			return CheckResult{
+				Status:       "failed",
+				ErrorMessage: "Failed to parse assertions: " + err.Error(),
+			}
+		}
+		if err := ValidateAssertions(assertions); err != nil {
+			return CheckResult{
+				Status:       "failed",
+				ErrorMessage: "Invalid assertions: " + err.Error(),
+			}
+		}
+	}
+
 	var bodyReader io.Reader
 	if mon.Body.Valid {
 		bodyReader = strings.NewReader(mon.Body.String)
 	}
 
-	req, err := http.NewRequest(mon.Method, parsedUrl.String(), bodyReader)
+	trace := &tracer{}
+	req, err := http.NewRequestWithContext(trace.withContext(ctx), mon.Method, parsedUrl.String(), bodyReader)
 	if err != nil {
 		return CheckResult{
 			Status:       "failed",
@@ -51,43 +72,112 @@ func HTTPMonitor(mon storage.Monitor) CheckResult {
 	}
 
 	// Add headers
-	if mon.Headers != nil {
-		var headers map[string][]string
-		err := json.Unmarshal(mon.Headers, &headers)
-		if err == nil {
-			for key, values := range headers {
-				for _, value := range values {
-					req.Header.Add(key, value)
-				}
-			}
+	for key, values := range monitorHeaders(mon) {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := do(client, req, trace, needsBody(assertions))
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			StatusCode:   resp.statusCode,
+			ErrorMessage: err.Error(),
 		}
 	}
 
+	result := CheckResult{
+		Status:         "success",
+		ResponseTimeMs: int(resp.elapsed.Milliseconds()),
+		StatusCode:     resp.statusCode,
+		Timings:        &resp.timings,
+		Certificates:   certificateChain(resp.tls),
+	}
+
+	// Check if status code matches expected (if specified)
+	if mon.ExpectedStatusCode.Valid && resp.statusCode != int(mon.ExpectedStatusCode.Int32) {
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Unexpected status code: got %d, expected %d", resp.statusCode, mon.ExpectedStatusCode.Int32)
+		return result
+	}
+
+	if msg := certificateExpiry(result.Certificates, int(mon.CertExpiryThresholdDays), resp.done); msg != "" {
+		if mon.CertExpiryAction == CertExpiryFail {
+			result.Status = "failed"
+			result.ErrorMessage = msg
+			return result
+		}
+		result.Warning = msg
+	}
+
+	if err := evaluate(assertions, resp.body, resp.header, resp.elapsed); err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = err.Error()
+	}
+
+	return result
+}
+
+// httpResponse is what a check needs from an HTTP response.
+type httpResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte // only kept when asked for
+	elapsed    time.Duration
+	done       time.Time
+	timings    Timings
+	tls        *tls.ConnectionState
+}
+
+// do sends req, which must carry trace's context, and reads the response
+// body. The body is kept only when keepBody is set. On failure the returned
+// response holds whatever was received.
+func do(client *http.Client, req *http.Request, trace *tracer, keepBody bool) (httpResponse, error) {
+	var result httpResponse
+
 	// Measure start time
 	startTime := time.Now()
 
 	// Perform the request
 	resp, err := client.Do(req)
 	if err != nil {
-		return CheckResult{
-			Status:       "failed",
-			ErrorMessage: "Request failed: " + err.Error(),
-		}
+		return result, fmt.Errorf("Request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	result.statusCode = resp.StatusCode
 
-	// Calculate response time
-	responseTime := time.Since(startTime).Milliseconds()
-
-	// Check if status code matches expected (if specified)
-	status := "success"
-	if mon.ExpectedStatusCode.Valid && resp.StatusCode != int(mon.ExpectedStatusCode.Int32) {
-		status = "failed"
+	// Read the body so the transfer counts towards the response time
+	if keepBody {
+		result.body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	} else {
+		_, err = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
 	}
+	result.done = time.Now()
+	if err != nil {
+		return result, fmt.Errorf("Failed to read response body: %w", err)
+	}
+
+	result.header = resp.Header
+	result.elapsed = result.done.Sub(startTime)
+	result.timings = trace.timings(result.done)
+	result.tls = resp.TLS
+	return result, nil
+}
 
-	return CheckResult{
-		Status:         status,
-		ResponseTimeMs: int(responseTime),
-		StatusCode:     resp.StatusCode,
+// monitorHeaders returns the headers configured on mon. Malformed headers are
+// ignored.
+func monitorHeaders(mon storage.Monitor) http.Header {
+	header := http.Header{}
+	if mon.Headers != nil {
+		var headers map[string][]string
+		if err := json.Unmarshal(mon.Headers, &headers); err == nil {
+			for key, values := range headers {
+				for _, value := range values {
+					header.Add(key, value)
+				}
+			}
+		}
 	}
+	return header
 }
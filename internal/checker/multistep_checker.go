@@ -0,0 +1,321 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// maxSteps caps the number of requests in a multi-step monitor.
+const maxSteps = 10
+
+// Extraction sources.
+const (
+	ExtractJSONPath = "json_path" // JSON value at Property in the body
+	ExtractHeader   = "header"    // response header Property
+	ExtractRegex    = "regex"     // first capture group of Property in the body
+)
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// MultiStepSettings are the type-specific settings of a multi-step monitor:
+// requests run in order, sharing cookies and extracted variables. A
+// variable is used as {{name}} in a later step's URL, headers or body.
+type MultiStepSettings struct {
+	Steps []Step `json:"steps"`
+}
+
+// Step is one request of a multi-step monitor.
+type Step struct {
+	Name               string            `json:"name"`
+	Method             string            `json:"method"`
+	URL                string            `json:"url"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	Body               string            `json:"body,omitempty"`
+	ExpectedStatusCode int               `json:"expected_status_code,omitempty"`
+	Assertions         []Assertion       `json:"assertions,omitempty"`
+	Extract            []Extraction      `json:"extract,omitempty"`
+}
+
+// Extraction copies a value from a step's response into a variable.
+type Extraction struct {
+	Variable string `json:"variable"`
+	Source   string `json:"source"`
+	Property string `json:"property"`
+}
+
+// StepResult is the outcome of one step, stored in the check details.
+type StepResult struct {
+	Name           string   `json:"name"`
+	Status         string   `json:"status"`
+	StatusCode     int      `json:"status_code,omitempty"`
+	ResponseTimeMs int      `json:"response_time_ms"`
+	Timings        *Timings `json:"timings,omitempty"`
+	ErrorMessage   string   `json:"error_message,omitempty"`
+}
+
+// MultiStepDetails is the outcome of a multi-step check, stored with the
+// check result.
+type MultiStepDetails struct {
+	Steps []StepResult `json:"steps"`
+}
+
+// ParseMultiStepSettings decodes and validates the settings of a multi-step
+// monitor.
+func ParseMultiStepSettings(raw []byte) (MultiStepSettings, error) {
+	var settings MultiStepSettings
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return settings, err
+		}
+	}
+
+	if len(settings.Steps) == 0 {
+		return settings, errors.New("at least one step is required")
+	}
+	if len(settings.Steps) > maxSteps {
+		return settings, fmt.Errorf("at most %d steps are allowed", maxSteps)
+	}
+
+	for i, step := range settings.Steps {
+		if err := step.validate(); err != nil {
+			return settings, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return settings, nil
+}
+
+func (s Step) validate() error {
+	if s.Method == "" {
+		return errors.New("method is required")
+	}
+	if s.URL == "" {
+		return errors.New("url is required")
+	}
+	if err := ValidateAssertions(s.Assertions); err != nil {
+		return err
+	}
+
+	for _, e := range s.Extract {
+		if !variableName.MatchString(e.Variable) {
+			return fmt.Errorf("invalid variable name %q", e.Variable)
+		}
+		switch e.Source {
+		case ExtractJSONPath:
+			if _, err := parseJSONPath(e.Property); err != nil {
+				return err
+			}
+		case ExtractHeader:
+			if e.Property == "" {
+				return errors.New("property must name a header")
+			}
+		case ExtractRegex:
+			re, err := regexp.Compile(e.Property)
+			if err != nil {
+				return fmt.Errorf("invalid regex: %w", err)
+			}
+			if re.NumSubexp() < 1 {
+				return fmt.Errorf("regex %q needs a capture group", e.Property)
+			}
+		default:
+			return fmt.Errorf("unknown extraction source %q", e.Source)
+		}
+	}
+	return nil
+}
+
+// needsBody reports whether the step's assertions or extractions read the
+// response body.
+func (s Step) needsBody() bool {
+	for _, e := range s.Extract {
+		if e.Source != ExtractHeader {
+			return true
+		}
+	}
+	return needsBody(s.Assertions)
+}
+
+func (c *Checker) checkMultiStep(ctx context.Context, mon storage.Monitor) CheckResult {
+	settings, err := ParseMultiStepSettings(mon.Settings)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Invalid settings: " + err.Error(),
+		}
+	}
+
+	tr, release, err := c.transport(mon)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Failed to configure transport: " + err.Error(),
+		}
+	}
+	defer release()
+
+	// Steps share cookies, so a login step can set up a session
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Failed to create cookie jar: " + err.Error(),
+		}
+	}
+	client := &http.Client{Transport: tr, Jar: jar}
+
+	// The timeout covers the whole transaction
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+	vars := map[string]string{}
+	details := &MultiStepDetails{Steps: make([]StepResult, 0, len(settings.Steps))}
+	result := CheckResult{Status: "success", Details: details}
+
+	for i, step := range settings.Steps {
+		stepResult := runStep(ctx, client, step, vars)
+		details.Steps = append(details.Steps, stepResult)
+		result.StatusCode = stepResult.StatusCode
+
+		if stepResult.Status != "success" {
+			name := step.Name
+			if name == "" {
+				name = step.Method + " " + step.URL
+			}
+			result.Status = "failed"
+			result.ErrorMessage = fmt.Sprintf("Step %d (%s): %s", i+1, name, stepResult.ErrorMessage)
+			break
+		}
+	}
+
+	result.ResponseTimeMs = int(time.Since(startTime).Milliseconds())
+	return result
+}
+
+// runStep sends one step's request with vars substituted, checks the
+// response and adds the step's extracted values to vars.
+func runStep(ctx context.Context, client *http.Client, step Step, vars map[string]string) StepResult {
+	result := StepResult{Name: step.Name, Status: "failed"}
+	expand := variableReplacer(vars, nil)
+
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(expand.Replace(step.Body))
+	}
+
+	trace := &tracer{}
+	req, err := http.NewRequestWithContext(trace.withContext(ctx), step.Method, expandURL(step.URL, vars), body)
+	if err != nil {
+		result.ErrorMessage = "Failed to create request: " + err.Error()
+		return result
+	}
+	for key, value := range step.Headers {
+		req.Header.Set(key, expand.Replace(value))
+	}
+
+	resp, err := do(client, req, trace, step.needsBody())
+	result.StatusCode = resp.statusCode
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	result.ResponseTimeMs = int(resp.elapsed.Milliseconds())
+	result.Timings = &resp.timings
+
+	if step.ExpectedStatusCode != 0 && resp.statusCode != step.ExpectedStatusCode {
+		result.ErrorMessage = fmt.Sprintf("Unexpected status code: got %d, expected %d", resp.statusCode, step.ExpectedStatusCode)
+		return result
+	}
+
+	if err := evaluate(step.Assertions, resp.body, resp.header, resp.elapsed); err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	for _, e := range step.Extract {
+		value, err := extract(e, resp)
+		if err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to extract %s: %s", e.Variable, err)
+			return result
+		}
+		vars[e.Variable] = value
+	}
+
+	result.Status = "success"
+	return result
+}
+
+// variableReplacer replaces each {{name}} placeholder with its value in
+// vars, passed through escape unless escape is nil.
+func variableReplacer(vars map[string]string, escape func(string) string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(vars))
+	for name, value := range vars {
+		if escape != nil {
+			value = escape(value)
+		}
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// expandURL substitutes vars into rawURL, escaping each value for the part
+// of the URL its placeholder sits in, so a value cannot add path segments,
+// query parameters or a fragment of its own.
+func expandURL(rawURL string, vars map[string]string) string {
+	path, rest := rawURL, ""
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		path, rest = rawURL[:i], rawURL[i:]
+	}
+	return variableReplacer(vars, url.PathEscape).Replace(path) +
+		variableReplacer(vars, url.QueryEscape).Replace(rest)
+}
+
+func extract(e Extraction, resp httpResponse) (string, error) {
+	switch e.Source {
+	case ExtractJSONPath:
+		var doc any
+		if err := json.Unmarshal(resp.body, &doc); err != nil {
+			return "", fmt.Errorf("body is not valid JSON: %w", err)
+		}
+		path, err := parseJSONPath(e.Property)
+		if err != nil {
+			return "", err
+		}
+		value, ok := lookupJSONPath(doc, path)
+		if !ok {
+			return "", fmt.Errorf("%s not found", e.Property)
+		}
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return jsonString(value), nil
+	case ExtractHeader:
+		value := resp.header.Get(e.Property)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found", e.Property)
+		}
+		return value, nil
+	case ExtractRegex:
+		re, err := regexp.Compile(e.Property)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(resp.body)
+		if match == nil {
+			return "", fmt.Errorf("body does not match %q", e.Property)
+		}
+		return string(match[1]), nil
+	default:
+		return "", fmt.Errorf("unknown extraction source %q", e.Source)
+	}
+}
@@ -0,0 +1,176 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	resp := httpResponse{
+		header: http.Header{"X-Request-Id": {"req-42"}},
+		body:   []byte(`{"token":"abc123","user":{"id":7,"roles":["admin"]},"ok":true} csrf=xyz789;`),
+	}
+	jsonResp := httpResponse{body: []byte(`{"token":"abc123","user":{"id":7,"roles":["admin"]},"ok":true}`)}
+
+	tests := []struct {
+		name       string
+		extraction Extraction
+		resp       httpResponse
+		want       string
+		wantErr    bool
+	}{
+		{"json string", Extraction{Source: ExtractJSONPath, Property: "$.token"}, jsonResp, "abc123", false},
+		{"json number", Extraction{Source: ExtractJSONPath, Property: "$.user.id"}, jsonResp, "7", false},
+		{"json bool", Extraction{Source: ExtractJSONPath, Property: "$.ok"}, jsonResp, "true", false},
+		{"json array element", Extraction{Source: ExtractJSONPath, Property: "$.user.roles[0]"}, jsonResp, "admin", false},
+		{"json object", Extraction{Source: ExtractJSONPath, Property: "$.user.roles"}, jsonResp, `["admin"]`, false},
+		{"json missing", Extraction{Source: ExtractJSONPath, Property: "$.session"}, jsonResp, "", true},
+		{"json invalid body", Extraction{Source: ExtractJSONPath, Property: "$.token"}, resp, "", true},
+		{"header", Extraction{Source: ExtractHeader, Property: "x-request-id"}, resp, "req-42", false},
+		{"header missing", Extraction{Source: ExtractHeader, Property: "X-Trace-Id"}, resp, "", true},
+		{"regex", Extraction{Source: ExtractRegex, Property: `csrf=(\w+);`}, resp, "xyz789", false},
+		{"regex no match", Extraction{Source: ExtractRegex, Property: `session=(\w+)`}, resp, "", true},
+		{"unknown source", Extraction{Source: "cookie", Property: "session"}, resp, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extract(tt.extraction, tt.resp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMultiStepSettingsExtract(t *testing.T) {
+	tests := []struct {
+		name       string
+		extraction string
+		wantErr    bool
+	}{
+		{"json path", `{"variable":"token","source":"json_path","property":"$.token"}`, false},
+		{"header", `{"variable":"request_id","source":"header","property":"X-Request-Id"}`, false},
+		{"regex", `{"variable":"csrf","source":"regex","property":"csrf=(\\w+)"}`, false},
+		{"invalid variable", `{"variable":"1token","source":"json_path","property":"$.token"}`, true},
+		{"invalid json path", `{"variable":"token","source":"json_path","property":"token"}`, true},
+		{"header without name", `{"variable":"token","source":"header"}`, true},
+		{"regex without group", `{"variable":"csrf","source":"regex","property":"csrf=\\w+"}`, true},
+		{"invalid regex", `{"variable":"csrf","source":"regex","property":"("}`, true},
+		{"unknown source", `{"variable":"token","source":"cookie","property":"session"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := fmt.Sprintf(`{"steps":[{"method":"GET","url":"https://example.com","extract":[%s]}]}`, tt.extraction)
+			_, err := ParseMultiStepSettings([]byte(raw))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMultiStepSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunStepPassesVariables(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Header().Set("X-Request-Id", "req-42")
+			fmt.Fprint(w, `{"token":"abc123","user":{"id":7}}`)
+		case "/users/7":
+			if r.Header.Get("Authorization") != "Bearer abc123" || r.Header.Get("X-Request-Id") != "req-42" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	vars := map[string]string{}
+	login := Step{
+		Method: "POST",
+		URL:    srv.URL + "/login",
+		Extract: []Extraction{
+			{Variable: "token", Source: ExtractJSONPath, Property: "$.token"},
+			{Variable: "user_id", Source: ExtractJSONPath, Property: "$.user.id"},
+			{Variable: "request_id", Source: ExtractHeader, Property: "X-Request-Id"},
+		},
+	}
+	if result := runStep(context.Background(), srv.Client(), login, vars); result.Status != "success" {
+		t.Fatalf("login step failed: %s", result.ErrorMessage)
+	}
+
+	want := map[string]string{"token": "abc123", "user_id": "7", "request_id": "req-42"}
+	for name, value := range want {
+		if vars[name] != value {
+			t.Errorf("vars[%s] = %q, want %q", name, vars[name], value)
+		}
+	}
+
+	profile := Step{
+		Method: "GET",
+		URL:    srv.URL + "/users/{{user_id}}",
+		Headers: map[string]string{
+			"Authorization": "Bearer {{token}}",
+			"X-Request-Id":  "{{request_id}}",
+		},
+		ExpectedStatusCode: http.StatusOK,
+	}
+	if result := runStep(context.Background(), srv.Client(), profile, vars); result.Status != "success" {
+		t.Errorf("step using extracted variables failed: %s", result.ErrorMessage)
+	}
+}
+
+func TestExpandURL(t *testing.T) {
+	vars := map[string]string{"id": "a/b?c#d", "q": "x&y=z #1", "plain": "abc123"}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"path", "https://example.com/users/{{id}}", "https://example.com/users/a%2Fb%3Fc%23d"},
+		{"query", "https://example.com/search?q={{q}}&page=1", "https://example.com/search?q=x%26y%3Dz+%231&page=1"},
+		{"path and query", "https://example.com/{{id}}?q={{id}}", "https://example.com/a%2Fb%3Fc%23d?q=a%2Fb%3Fc%23d"},
+		{"fragment", "https://example.com/docs#{{q}}", "https://example.com/docs#x%26y%3Dz+%231"},
+		{"unchanged", "https://example.com/{{plain}}?token={{plain}}", "https://example.com/abc123?token=abc123"},
+		{"unknown variable", "https://example.com/{{missing}}", "https://example.com/{{missing}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandURL(tt.url, vars); got != tt.want {
+				t.Errorf("expandURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStepExtractionFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	vars := map[string]string{}
+	step := Step{
+		Method:  "GET",
+		URL:     srv.URL,
+		Extract: []Extraction{{Variable: "token", Source: ExtractJSONPath, Property: "$.token"}},
+	}
+
+	result := runStep(context.Background(), srv.Client(), step, vars)
+	if result.Status != "failed" || result.ErrorMessage != "Failed to extract token: $.token not found" {
+		t.Errorf("runStep() = %s %q, want the extraction to fail", result.Status, result.ErrorMessage)
+	}
+	if _, ok := vars["token"]; ok {
+		t.Error("failed extraction set a variable")
+	}
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// maxBannerBytes caps how much a TCP check reads while looking for the
+// expected banner.
+const maxBannerBytes = 4096
+
+// TCPSettings are the type-specific settings of a TCP monitor. The monitor's
+// url holds the host:port to dial and its body the optional payload to send.
+type TCPSettings struct {
+	// ExpectBanner is a substring the service must send back.
+	ExpectBanner string `json:"expect_banner,omitempty"`
+}
+
+// ValidateTCPTarget reports whether target is a host:port a TCP check can dial.
+func ValidateTCPTarget(target string) error {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return err
+	}
+	if host == "" || port == "" {
+		return errors.New("host and port are required")
+	}
+	return nil
+}
+
+func (c *Checker) checkTCP(ctx context.Context, mon storage.Monitor) CheckResult {
+	var settings TCPSettings
+	if len(mon.Settings) > 0 {
+		if err := json.Unmarshal(mon.Settings, &settings); err != nil {
+			return CheckResult{
+				Status:       "failed",
+				ErrorMessage: "Failed to parse settings: " + err.Error(),
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", mon.Url)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Connection failed: " + err.Error(),
+		}
+	}
+	defer conn.Close()
+
+	// Reads and writes have no context, so bound them by the same deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if mon.Body.Valid && mon.Body.String != "" {
+		if _, err := conn.Write([]byte(mon.Body.String)); err != nil {
+			return CheckResult{
+				Status:         "failed",
+				ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+				ErrorMessage:   "Failed to send payload: " + err.Error(),
+			}
+		}
+	}
+
+	if settings.ExpectBanner != "" {
+		if err := readBanner(conn, settings.ExpectBanner); err != nil {
+			return CheckResult{
+				Status:         "failed",
+				ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+				ErrorMessage:   err.Error(),
+			}
+		}
+	}
+
+	return CheckResult{
+		Status:         "success",
+		ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+	}
+}
+
+// readBanner reads from conn until it has seen expect, the connection is
+// closed or maxBannerBytes have been read.
+func readBanner(conn net.Conn, expect string) error {
+	buf := make([]byte, 0, maxBannerBytes)
+	chunk := make([]byte, 512)
+
+	for len(buf) < maxBannerBytes {
+		n, err := conn.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if strings.Contains(string(buf), expect) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Banner does not contain %q: %w", expect, err)
+		}
+	}
+
+	return fmt.Errorf("Banner does not contain %q", expect)
+}
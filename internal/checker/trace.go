@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings breaks an HTTP check down into phases, in milliseconds. Phases that
+// didn't happen are zero, e.g. DNS for an IP address, TLS for plain HTTP, or
+// DNS, connect and TLS when a pooled connection was reused.
+type Timings struct {
+	DNSMs      int `json:"dns_ms"`      // resolving the host name
+	ConnectMs  int `json:"connect_ms"`  // establishing the TCP connection
+	TLSMs      int `json:"tls_ms"`      // the TLS handshake
+	TTFBMs     int `json:"ttfb_ms"`     // from the request being written to the first response byte
+	TransferMs int `json:"transfer_ms"` // reading the response body
+}
+
+// tracer records connection phase timestamps via net/http/httptrace. Hooks may
+// fire from several goroutines when dialing multiple addresses, so access is
+// guarded by a mutex.
+type tracer struct {
+	mu                        sync.Mutex
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest              time.Time
+	firstByte                 time.Time
+}
+
+func (t *tracer) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.mark(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	})
+}
+
+func (t *tracer) mark(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+// timings converts the recorded phases, with done being when the response
+// body was fully read.
+func (t *tracer) timings(done time.Time) Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return Timings{
+		DNSMs:      between(t.dnsStart, t.dnsDone),
+		ConnectMs:  between(t.connectStart, t.connectDone),
+		TLSMs:      between(t.tlsStart, t.tlsDone),
+		TTFBMs:     between(t.wroteRequest, t.firstByte),
+		TransferMs: between(t.firstByte, done),
+	}
+}
+
+func between(start, end time.Time) int {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return int(end.Sub(start).Milliseconds())
+}
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/coder/websocket"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// maxReplyDetailBytes caps how much of a WebSocket reply is kept in the
+// check details.
+const maxReplyDetailBytes = 256
+
+// WebSocketSettings are the type-specific settings of a WebSocket monitor.
+// The monitor's url holds the ws:// or wss:// endpoint and its headers are
+// sent with the handshake.
+type WebSocketSettings struct {
+	// Message is a text frame sent once connected.
+	Message string `json:"message,omitempty"`
+	// Expect is a regular expression a received message must match. When it
+	// is empty but Message is set, any reply will do.
+	Expect string `json:"expect,omitempty"`
+}
+
+// WebSocketDetails is the outcome of a WebSocket check, stored with the
+// check result.
+type WebSocketDetails struct {
+	HandshakeMs int    `json:"handshake_ms"`
+	RoundTripMs *int   `json:"round_trip_ms,omitempty"`
+	Reply       string `json:"reply,omitempty"`
+}
+
+// ParseWebSocketSettings decodes and validates the settings of a WebSocket
+// monitor.
+func ParseWebSocketSettings(raw []byte) (WebSocketSettings, error) {
+	var settings WebSocketSettings
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return settings, err
+		}
+	}
+	if settings.Expect != "" {
+		if _, err := regexp.Compile(settings.Expect); err != nil {
+			return settings, fmt.Errorf("invalid expect pattern: %w", err)
+		}
+	}
+	return settings, nil
+}
+
+// ValidateWebSocketURL reports whether target is a ws:// or wss:// URL.
+func ValidateWebSocketURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+		return fmt.Errorf("%q is not a ws:// or wss:// url", target)
+	}
+	return nil
+}
+
+func (c *Checker) checkWebSocket(ctx context.Context, mon storage.Monitor) CheckResult {
+	settings, err := ParseWebSocketSettings(mon.Settings)
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Invalid settings: " + err.Error(),
+		}
+	}
+
+	var expect *regexp.Regexp
+	if settings.Expect != "" {
+		expect = regexp.MustCompile(settings.Expect)
+	}
+
+	// The upgraded connection is hijacked and never returns to a pool, so
+	// each check gets its own HTTP/1.1 transport.
+	tr, err := newTransport(transportKey{
+		skipTLSVerify: mon.SkipTlsVerify,
+		proxyURL:      mon.ProxyUrl.String,
+	})
+	if err != nil {
+		return CheckResult{
+			Status:       "failed",
+			ErrorMessage: "Failed to configure transport: " + err.Error(),
+		}
+	}
+	tr.ForceAttemptHTTP2 = false
+	defer tr.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(mon.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	startTime := time.Now()
+
+	conn, resp, err := websocket.Dial(ctx, mon.Url, &websocket.DialOptions{
+		HTTPClient: &http.Client{Transport: tr},
+		HTTPHeader: monitorHeaders(mon),
+	})
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	if err != nil {
+		return CheckResult{
+			Status:         "failed",
+			ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+			StatusCode:     statusCode,
+			ErrorMessage:   "Handshake failed: " + err.Error(),
+		}
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxBodyBytes)
+
+	details := WebSocketDetails{HandshakeMs: int(time.Since(startTime).Milliseconds())}
+	result := CheckResult{
+		Status:     "success",
+		StatusCode: statusCode,
+		Details:    &details,
+	}
+
+	if settings.Message != "" || expect != nil {
+		sentTime := time.Now()
+		if err := exchange(ctx, conn, settings.Message, expect, &details); err != nil {
+			result.Status = "failed"
+			result.ErrorMessage = err.Error()
+		} else {
+			roundTrip := int(time.Since(sentTime).Milliseconds())
+			details.RoundTripMs = &roundTrip
+		}
+	}
+
+	result.ResponseTimeMs = int(time.Since(startTime).Milliseconds())
+	if result.Status == "success" {
+		conn.Close(websocket.StatusNormalClosure, "")
+	}
+	return result
+}
+
+// exchange sends message, if any, and reads until a message matches expect.
+// Without a pattern the first message received is the reply.
+func exchange(ctx context.Context, conn *websocket.Conn, message string, expect *regexp.Regexp, details *WebSocketDetails) error {
+	if message != "" {
+		if err := conn.Write(ctx, websocket.MessageText, []byte(message)); err != nil {
+			return fmt.Errorf("Failed to send message: %w", err)
+		}
+	}
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			if expect != nil {
+				return fmt.Errorf("No reply matching %q: %w", expect.String(), err)
+			}
+			return fmt.Errorf("No reply: %w", err)
+		}
+
+		details.Reply = string(data[:min(len(data), maxReplyDetailBytes)])
+		if expect == nil || expect.Match(data) {
+			return nil
+		}
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -10,6 +11,25 @@ type Config struct {
 	Port        string
 	DatabaseURL string
 	JWTSecret   string
+	SecretKey   string
+	// AppURL is where users open monitor-bee, used for links in alerts
+	AppURL string
+
+	WorkerConcurrency int
+	WorkerPerHost     int
+	WorkerQueueDepth  int
+
+	// Email alerts, including those every monitor owner gets, are disabled
+	// when SMTPHost is empty
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Base URLs of the paging services, overridable for testing
+	PagerDutyURL string
+	OpsgenieURL  string
 }
 
 func Load() *Config {
@@ -19,6 +39,21 @@ func Load() *Config {
 		Port:        getEnv("PORT", ":8080"),
 		DatabaseURL: getEnv("DATABASE_URL", "postgres://localhost/myapp?sslmode=disable"),
 		JWTSecret:   getEnv("JWT_SECRET", "your-secret-key"),
+		SecretKey:   getEnv("SECRET_KEY", ""),
+		AppURL:      getEnv("APP_URL", "http://localhost:8080"),
+
+		WorkerConcurrency: getEnvInt("WORKER_CONCURRENCY", 50),
+		WorkerPerHost:     getEnvInt("WORKER_PER_HOST", 4),
+		WorkerQueueDepth:  getEnvInt("WORKER_QUEUE_DEPTH", 1000),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "monitor-bee@localhost"),
+
+		PagerDutyURL: getEnv("PAGERDUTY_URL", "https://events.pagerduty.com"),
+		OpsgenieURL:  getEnv("OPSGENIE_URL", "https://api.opsgenie.com"),
 	}
 }
 
@@ -28,3 +63,10 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
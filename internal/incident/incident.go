@@ -0,0 +1,247 @@
+// Package incident turns monitor state transitions into incidents: one is
+// opened when a monitor goes down and resolved when it comes back up. Each
+// incident keeps a timeline of these system events alongside the
+// acknowledgements, notes and postmortem edits made by users. An incident on
+// a monitor with an escalation policy is escalated until it is acknowledged
+// or resolved.
+package incident
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/state"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Incident states.
+const (
+	Open     = "open"
+	Resolved = "resolved"
+)
+
+// Timeline event types.
+const (
+	EventFirstFailure = "first_failure"
+	EventRecovered    = "recovered"
+	EventAcknowledged = "acknowledged"
+	EventNote         = "note"
+	EventPostmortem   = "postmortem_updated"
+	EventEscalated    = "escalated"
+)
+
+// Reasons an escalation stopped.
+const (
+	StopAcknowledged = "acknowledged"
+	StopResolved     = "resolved"
+	// StopExhausted means every level of the policy was notified.
+	StopExhausted = "exhausted"
+)
+
+// ErrAcknowledged is returned when acknowledging an incident that already
+// has been.
+var ErrAcknowledged = errors.New("incident is already acknowledged")
+
+// Service opens and resolves incidents. It is a state.Handler.
+type Service struct {
+	store  *storage.Store
+	logger *slog.Logger
+}
+
+func NewService(store *storage.Store, logger *slog.Logger) *Service {
+	return &Service{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// HandleTransition opens an incident when a monitor goes down and resolves
+// it when the monitor recovers.
+func (s *Service) HandleTransition(ctx context.Context, q *storage.Queries, t state.Transition) error {
+	switch {
+	case t.To == state.Down:
+		return s.open(ctx, q, t)
+	case t.From == state.Down && t.To == state.Up:
+		return s.resolve(ctx, q, t)
+	}
+	return nil
+}
+
+// open records an incident for the failed checks that took the monitor
+// down. The incident starts at the first of them.
+func (s *Service) open(ctx context.Context, q *storage.Queries, t state.Transition) error {
+	checks, err := q.ListTriggeringChecks(ctx, storage.ListTriggeringChecksParams{
+		MonitorID: t.Monitor.ID,
+		CheckID:   t.Check.ID,
+		Failures:  t.State.ConsecutiveFailures,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	first := t.Check
+	ids := make([]int32, 0, len(checks))
+	for _, c := range checks {
+		ids = append(ids, c.ID)
+		if c.CheckedAt.Time.Before(first.CheckedAt.Time) {
+			first = c
+		}
+	}
+
+	inc, err := q.OpenIncident(ctx, storage.OpenIncidentParams{
+		MonitorID: t.Monitor.ID,
+		Cause:     t.Check.ErrorMessage,
+		StartedAt: first.CheckedAt,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The monitor already has an open incident
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	err = q.AttachIncidentChecks(ctx, storage.AttachIncidentChecksParams{
+		IncidentID: inc.ID,
+		CheckIds:   ids,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	_, err = q.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+		IncidentID: inc.ID,
+		Type:       EventFirstFailure,
+		CheckID:    pgtype.Int4{Int32: first.ID, Valid: true},
+		Message:    first.ErrorMessage,
+		OccurredAt: first.CheckedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	if err := q.StartEscalation(ctx, inc.ID); err != nil {
+		return fmt.Errorf("failed to open incident: %w", err)
+	}
+
+	s.logger.Warn("incident opened", "incident_id", inc.ID, "monitor_id", t.Monitor.ID)
+	return nil
+}
+
+// resolve closes the monitor's open incident at the time of the check that
+// confirmed recovery.
+func (s *Service) resolve(ctx context.Context, q *storage.Queries, t state.Transition) error {
+	inc, err := q.ResolveIncident(ctx, storage.ResolveIncidentParams{
+		ResolvedAt: t.Check.CheckedAt,
+		MonitorID:  t.Monitor.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	_, err = q.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+		IncidentID: inc.ID,
+		Type:       EventRecovered,
+		CheckID:    pgtype.Int4{Int32: t.Check.ID, Valid: true},
+		OccurredAt: t.Check.CheckedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	err = q.StopEscalation(ctx, storage.StopEscalationParams{
+		IncidentID: inc.ID,
+		StopReason: pgtype.Text{String: StopResolved, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	s.logger.Info("incident resolved",
+		"incident_id", inc.ID,
+		"monitor_id", t.Monitor.ID,
+		"duration_seconds", inc.DurationSeconds.Int32,
+	)
+	return nil
+}
+
+// Acknowledge marks inc as acknowledged by userID, which stops its
+// escalation.
+func (s *Service) Acknowledge(ctx context.Context, inc storage.Incident, userID int32) (storage.Incident, error) {
+	acked := inc
+
+	err := s.store.ExecTx(ctx, func(q *storage.Queries) error {
+		var err error
+		acked, err = q.AcknowledgeIncident(ctx, storage.AcknowledgeIncidentParams{
+			ID:             inc.ID,
+			AcknowledgedBy: pgtype.Int4{Int32: userID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+			IncidentID: inc.ID,
+			Type:       EventAcknowledged,
+			UserID:     pgtype.Int4{Int32: userID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.StopEscalation(ctx, storage.StopEscalationParams{
+			IncidentID: inc.ID,
+			StopReason: pgtype.Text{String: StopAcknowledged, Valid: true},
+		})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return inc, ErrAcknowledged
+	}
+	if err != nil {
+		return inc, err
+	}
+
+	s.logger.Info("incident acknowledged", "incident_id", inc.ID, "user_id", userID)
+	return acked, nil
+}
+
+// AddNote adds a note by userID to the timeline of incidentID.
+func (s *Service) AddNote(ctx context.Context, incidentID, userID int32, message string) (storage.IncidentEvent, error) {
+	return s.store.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+		IncidentID: incidentID,
+		Type:       EventNote,
+		UserID:     pgtype.Int4{Int32: userID, Valid: true},
+		Message:    pgtype.Text{String: message, Valid: true},
+	})
+}
+
+// UpdatePostmortem replaces the root cause, resolution and postmortem of an
+// incident and records who changed them.
+func (s *Service) UpdatePostmortem(ctx context.Context, params storage.UpdateIncidentPostmortemParams, userID int32) (storage.Incident, error) {
+	var inc storage.Incident
+
+	err := s.store.ExecTx(ctx, func(q *storage.Queries) error {
+		var err error
+		inc, err = q.UpdateIncidentPostmortem(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateIncidentEvent(ctx, storage.CreateIncidentEventParams{
+			IncidentID: inc.ID,
+			Type:       EventPostmortem,
+			UserID:     pgtype.Int4{Int32: userID, Valid: true},
+		})
+		return err
+	})
+	return inc, err
+}
+
+var _ state.Handler = (*Service)(nil)
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/checker"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// heartbeatSweepInterval is how often the leader looks for heartbeat
+// monitors whose ping is overdue.
+const heartbeatSweepInterval = 10 * time.Second
+
+// watchHeartbeats records a failed check for every heartbeat monitor that
+// misses its ping, until ctx is cancelled.
+func (s *Scheduler) watchHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		monitors, err := s.store.ClaimMissedHeartbeats(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("failed to claim missed heartbeats", "error", err)
+			}
+			continue
+		}
+
+		for _, mon := range monitors {
+			if err := s.recordMissedHeartbeat(ctx, mon); err != nil {
+				s.logger.Error("failed to record missed heartbeat", "monitor_id", mon.ID, "error", err)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) recordMissedHeartbeat(ctx context.Context, mon storage.Monitor) error {
+	details, err := json.Marshal(checker.HeartbeatDetails{Event: checker.HeartbeatMissed})
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("No ping received within %d seconds", mon.IntervalSeconds+mon.GraceSeconds)
+	check, err := s.store.CreateMonitorCheck(ctx, storage.CreateMonitorCheckParams{
+		MonitorID:    mon.ID,
+		Status:       "failed",
+		Attempts:     1,
+		ErrorMessage: pgtype.Text{String: msg, Valid: true},
+		Details:      details,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Warn("heartbeat missed", "monitor_id", mon.ID)
+	return s.tracker.Record(ctx, mon, check)
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	// leaderLockKey identifies the Postgres advisory lock held by the
+	// instance that schedules checks.
+	leaderLockKey int64 = 0x6d6f6e69746f72 // "monitor"
+
+	// electionInterval is how often a follower tries to become leader.
+	electionInterval = 5 * time.Second
+
+	// leaseCheckInterval is how often the leader confirms it still holds
+	// the lock. It bounds how long two instances can both believe they lead
+	// after the leader loses its database session.
+	leaseCheckInterval = 5 * time.Second
+)
+
+// Run takes part in leader election until ctx is cancelled. Only the instance
+// holding the leader lock schedules checks; the others stand by and take over
+// when the leader shuts down, crashes or loses its database connection.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		lock, err := s.store.TryAdvisoryLock(ctx, leaderLockKey)
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				s.logger.Error("leader election failed", "error", err)
+			}
+		case lock != nil:
+			s.logger.Info("elected scheduler leader")
+			if err := s.leadWhileHeld(ctx, lock); err != nil {
+				s.logger.Error("scheduler failed", "error", err)
+			}
+			lock.Release()
+			if ctx.Err() == nil {
+				s.logger.Warn("lost scheduler leadership")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(electionInterval):
+		}
+	}
+}
+
+// leadWhileHeld schedules checks until ctx is cancelled or the lock's session
+// stops responding.
+func (s *Scheduler) leadWhileHeld(ctx context.Context, lock *storage.AdvisoryLock) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(leaseCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Ping(ctx); err != nil {
+					if ctx.Err() == nil {
+						s.logger.Error("leader lock connection lost", "error", err)
+					}
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return s.lead(ctx)
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrQueueFull  = errors.New("check queue is full")
+	ErrPoolClosed = errors.New("worker pool is closed")
+)
+
+type PoolConfig struct {
+	Workers    int // checks running at once across all hosts
+	PerHost    int // checks running at once against a single host
+	QueueDepth int // checks waiting for a worker before new ones are rejected
+}
+
+type PoolStats struct {
+	Queued    int
+	Running   int
+	Submitted uint64
+	Completed uint64
+	Rejected  uint64
+}
+
+// Pool runs checks on a fixed number of workers. Tasks wait in a bounded
+// queue and are picked in order, skipping tasks whose host already has
+// PerHost checks running so one busy origin can't hold up the others.
+type Pool struct {
+	cfg PoolConfig
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []task
+	running map[string]int
+	closed  bool
+	stats   PoolStats
+	wg      sync.WaitGroup
+}
+
+type task struct {
+	host string
+	run  func()
+}
+
+func NewPool(cfg PoolConfig) *Pool {
+	cfg.Workers = max(cfg.Workers, 1)
+	cfg.PerHost = max(cfg.PerHost, 1)
+	cfg.QueueDepth = max(cfg.QueueDepth, 1)
+
+	p := &Pool{
+		cfg:     cfg,
+		running: make(map[string]int),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	p.wg.Add(cfg.Workers)
+	for range cfg.Workers {
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues run to be executed against host. It never blocks: when the
+// queue is full the task is rejected with ErrQueueFull.
+func (p *Pool) Submit(host string, run func()) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+	if len(p.queue) >= p.cfg.QueueDepth {
+		p.stats.Rejected++
+		return ErrQueueFull
+	}
+
+	p.queue = append(p.queue, task{host: host, run: run})
+	p.stats.Submitted++
+	p.cond.Signal()
+	return nil
+}
+
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Queued = len(p.queue)
+	return stats
+}
+
+// Free reports how many more tasks the queue can take right now.
+func (p *Pool) Free() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cfg.QueueDepth - len(p.queue)
+}
+
+// Close stops accepting tasks and waits for queued and running ones to
+// finish.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		t, ok := p.nextLocked()
+		for !ok {
+			if p.closed && len(p.queue) == 0 {
+				return
+			}
+			p.cond.Wait()
+			t, ok = p.nextLocked()
+		}
+
+		p.running[t.host]++
+		p.stats.Running++
+		p.mu.Unlock()
+
+		t.run()
+
+		p.mu.Lock()
+		if p.running[t.host]--; p.running[t.host] == 0 {
+			delete(p.running, t.host)
+		}
+		p.stats.Running--
+		p.stats.Completed++
+		// A host slot was freed, so a task skipped earlier may now be runnable.
+		p.cond.Broadcast()
+	}
+}
+
+// nextLocked removes and returns the oldest queued task whose host is below
+// its concurrency limit.
+func (p *Pool) nextLocked() (task, bool) {
+	for i, t := range p.queue {
+		if p.running[t.host] < p.cfg.PerHost {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			return t, true
+		}
+	}
+	return task{}, false
+}
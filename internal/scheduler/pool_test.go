@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoolLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   PoolConfig
+		hosts []string
+	}{
+		{
+			name:  "one check per host",
+			cfg:   PoolConfig{Workers: 4, PerHost: 1, QueueDepth: 16},
+			hosts: []string{"a", "a", "a", "b", "b", "b", "c", "c"},
+		},
+		{
+			name:  "several checks per host",
+			cfg:   PoolConfig{Workers: 4, PerHost: 2, QueueDepth: 16},
+			hosts: []string{"a", "a", "a", "a", "a", "a"},
+		},
+		{
+			name:  "workers bound every host",
+			cfg:   PoolConfig{Workers: 2, PerHost: 5, QueueDepth: 16},
+			hosts: []string{"a", "a", "a", "b", "b", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPool(tt.cfg)
+
+			var mu sync.Mutex
+			total, maxTotal := 0, 0
+			perHost := make(map[string]int)
+			maxPerHost := 0
+
+			for _, host := range tt.hosts {
+				err := p.Submit(host, func() {
+					mu.Lock()
+					total++
+					perHost[host]++
+					maxTotal = max(maxTotal, total)
+					maxPerHost = max(maxPerHost, perHost[host])
+					mu.Unlock()
+
+					time.Sleep(20 * time.Millisecond)
+
+					mu.Lock()
+					total--
+					perHost[host]--
+					mu.Unlock()
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			p.Close()
+
+			if maxTotal > tt.cfg.Workers {
+				t.Errorf("%d checks ran at once, want at most %d", maxTotal, tt.cfg.Workers)
+			}
+			if maxPerHost > tt.cfg.PerHost {
+				t.Errorf("%d checks ran at once against one host, want at most %d", maxPerHost, tt.cfg.PerHost)
+			}
+			if stats := p.Stats(); stats.Completed != uint64(len(tt.hosts)) {
+				t.Errorf("completed %d checks, want %d", stats.Completed, len(tt.hosts))
+			}
+		})
+	}
+}
+
+func TestPoolSkipsBusyHost(t *testing.T) {
+	p := NewPool(PoolConfig{Workers: 2, PerHost: 1, QueueDepth: 4})
+	defer p.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit("slow.example.com", func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// The second slow check must wait, but the fast one queued after it
+	// should not
+	ranSlow := make(chan struct{})
+	if err := p.Submit("slow.example.com", func() { close(ranSlow) }); err != nil {
+		t.Fatal(err)
+	}
+	ranFast := make(chan struct{})
+	if err := p.Submit("fast.example.com", func() { close(ranFast) }); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ranFast:
+	case <-time.After(time.Second):
+		t.Fatal("check against an idle host waited behind a busy one")
+	}
+
+	select {
+	case <-ranSlow:
+		t.Fatal("second check against a busy host ran past its limit")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-ranSlow:
+	case <-time.After(time.Second):
+		t.Fatal("queued check did not run once its host was free")
+	}
+}
+
+func TestPoolQueueFull(t *testing.T) {
+	p := NewPool(PoolConfig{Workers: 1, PerHost: 1, QueueDepth: 2})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	if err := p.Submit("a", func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	for i := range 2 {
+		if err := p.Submit(fmt.Sprint("queued-", i), func() {}); err != nil {
+			t.Fatalf("Submit() queued task %d error = %v", i, err)
+		}
+	}
+	if free := p.Free(); free != 0 {
+		t.Errorf("Free() = %d, want 0", free)
+	}
+	if err := p.Submit("b", func() {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit() on a full queue error = %v, want %v", err, ErrQueueFull)
+	}
+
+	stats := p.Stats()
+	if stats.Queued != 2 || stats.Running != 1 || stats.Submitted != 3 || stats.Rejected != 1 {
+		t.Errorf("Stats() = %+v, want 2 queued, 1 running, 3 submitted and 1 rejected", stats)
+	}
+
+	close(release)
+	p.Close()
+
+	if stats := p.Stats(); stats.Completed != 3 || stats.Queued != 0 {
+		t.Errorf("Stats() after Close = %+v, want every accepted task completed", stats)
+	}
+	if err := p.Submit("c", func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit() after Close error = %v, want %v", err, ErrPoolClosed)
+	}
+}
@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rammyblog/monitor-bee/internal/checker"
+	"github.com/rammyblog/monitor-bee/internal/state"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	// changeDelay is the most a created or edited monitor waits before its
+	// first check with the new settings.
+	changeDelay = 5 * time.Second
+
+	// listenRetryDelay is how long to wait before re-subscribing to monitor
+	// changes after the listener connection fails.
+	listenRetryDelay = 5 * time.Second
+
+	// maxJobAttempts is how many times a check job is tried before it is
+	// moved to the dead letter state.
+	maxJobAttempts = 3
+)
+
+// Scheduler decides when each active monitor is due and enqueues a check job
+// for it. The jobs are executed by Workers.
+type Scheduler struct {
+	store   *storage.Store
+	tracker *state.Tracker
+	logger  *slog.Logger
+
+	mu      sync.Mutex
+	jobs    map[int32]*job
+	stopped bool
+}
+
+type job struct {
+	monitor storage.Monitor
+	timer   *time.Timer
+}
+
+func New(store *storage.Store, tracker *state.Tracker, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		store:   store,
+		tracker: tracker,
+		logger:  logger,
+		jobs:    make(map[int32]*job),
+	}
+}
+
+// lead loads every active monitor and enqueues a check for each one on its
+// interval until ctx is cancelled, applying monitor changes as they are
+// announced on storage.MonitorChangesChannel.
+func (s *Scheduler) lead(ctx context.Context) error {
+	monitors, err := s.store.ListActiveMonitors(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load active monitors: %w", err)
+	}
+
+	s.mu.Lock()
+	s.stopped = false
+	for _, mon := range monitors {
+		// Spread the first round of checks across the interval so monitors
+		// created together don't all fire at once.
+		s.scheduleLocked(ctx, mon, randomDuration(interval(mon)))
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("scheduler started", "monitors", len(monitors))
+
+	heartbeatsDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatsDone)
+		s.watchHeartbeats(ctx)
+	}()
+
+	s.watch(ctx)
+	<-heartbeatsDone
+
+	s.mu.Lock()
+	s.stopped = true
+	for id, j := range s.jobs {
+		j.timer.Stop()
+		delete(s.jobs, id)
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("scheduler stopped")
+	return nil
+}
+
+// watch applies monitor change notifications until ctx is cancelled. If the
+// listener connection drops, every active monitor is reloaded once it is back
+// so changes made in the meantime aren't missed.
+func (s *Scheduler) watch(ctx context.Context) {
+	for {
+		err := s.store.Listen(ctx, storage.MonitorChangesChannel, func(payload string) {
+			id, err := strconv.Atoi(payload)
+			if err != nil {
+				s.logger.Warn("invalid monitor change payload", "payload", payload)
+				return
+			}
+			s.reconcile(ctx, int32(id))
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("monitor change listener stopped", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+
+		if err := s.resync(ctx); err != nil {
+			s.logger.Error("failed to reload monitors", "error", err)
+		}
+	}
+}
+
+// reconcile brings the schedule for one monitor in line with the database.
+func (s *Scheduler) reconcile(ctx context.Context, id int32) {
+	mon, err := s.store.GetMonitor(ctx, id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Error("failed to load changed monitor", "monitor_id", id, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+
+	if err != nil || mon.Status != "active" {
+		s.unscheduleLocked(id)
+		return
+	}
+	s.applyLocked(ctx, mon)
+}
+
+// resync reloads every active monitor and reconciles the whole schedule.
+func (s *Scheduler) resync(ctx context.Context) error {
+	monitors, err := s.store.ListActiveMonitors(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return nil
+	}
+
+	active := make(map[int32]bool, len(monitors))
+	for _, mon := range monitors {
+		active[mon.ID] = true
+		s.applyLocked(ctx, mon)
+	}
+	for id := range s.jobs {
+		if !active[id] {
+			s.unscheduleLocked(id)
+		}
+	}
+	return nil
+}
+
+// applyLocked schedules mon unless it is already scheduled with the same
+// settings. New and edited monitors are checked within changeDelay.
+func (s *Scheduler) applyLocked(ctx context.Context, mon storage.Monitor) {
+	if j, ok := s.jobs[mon.ID]; ok {
+		if j.monitor.UpdatedAt.Time.Equal(mon.UpdatedAt.Time) {
+			return
+		}
+		j.timer.Stop()
+		delete(s.jobs, mon.ID)
+	}
+	s.scheduleLocked(ctx, mon, randomDuration(min(changeDelay, interval(mon))))
+}
+
+func (s *Scheduler) unscheduleLocked(id int32) {
+	if j, ok := s.jobs[id]; ok {
+		j.timer.Stop()
+		delete(s.jobs, id)
+	}
+}
+
+func (s *Scheduler) scheduleLocked(ctx context.Context, mon storage.Monitor, delay time.Duration) {
+	if mon.Type == checker.TypeHeartbeat {
+		// Pinged rather than polled; watchHeartbeats catches missed pings.
+		return
+	}
+
+	j := &job{monitor: mon}
+	j.timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		if s.stopped || s.jobs[mon.ID] != j {
+			s.mu.Unlock()
+			return
+		}
+		j.timer.Reset(withJitter(interval(mon)))
+		s.mu.Unlock()
+
+		s.enqueue(ctx, mon)
+	})
+	s.jobs[mon.ID] = j
+}
+
+func (s *Scheduler) enqueue(ctx context.Context, mon storage.Monitor) {
+	n, err := s.store.EnqueueCheckJob(ctx, storage.EnqueueCheckJobParams{
+		MonitorID:   mon.ID,
+		MaxAttempts: maxJobAttempts,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to enqueue check", "monitor_id", mon.ID, "error", err)
+		}
+		return
+	}
+	if n == 0 {
+		s.logger.Warn("skipping check, previous one still pending", "monitor_id", mon.ID)
+	}
+}
+
+func interval(mon storage.Monitor) time.Duration {
+	return time.Duration(mon.IntervalSeconds) * time.Second
+}
+
+// withJitter returns d shifted by up to 10% in either direction.
+func withJitter(d time.Duration) time.Duration {
+	spread := d / 10
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + randomDuration(2*spread)
+}
+
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
@@ -0,0 +1,385 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/checker"
+	"github.com/rammyblog/monitor-bee/internal/state"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	// pollInterval is how often the worker looks for due check jobs.
+	pollInterval = time.Second
+
+	// maxClaimBatch caps how many jobs are claimed in one poll.
+	maxClaimBatch = 100
+
+	// leaseMarginSeconds is added to the time a monitor's check may take,
+	// retries included, to form the lease on its job. A job still running
+	// when its lease expires is assumed to belong to a crashed worker and is
+	// claimed again.
+	leaseMarginSeconds = 30
+
+	// releaseTimeout bounds the queue updates made while shutting down.
+	releaseTimeout = 5 * time.Second
+
+	// checkRetryDelay is the pause before a failed check is retried.
+	checkRetryDelay = time.Second
+
+	retryBaseDelay = 5 * time.Second
+	retryMaxDelay  = time.Minute
+
+	// statsInterval is how often worker pool stats are logged.
+	statsInterval = time.Minute
+)
+
+// Worker claims due jobs from the check_jobs queue and runs them on a Pool.
+// Every instance runs one; SKIP LOCKED hands each job to a single worker.
+type Worker struct {
+	store   *storage.Store
+	checker *checker.Checker
+	tracker *state.Tracker
+	logger  *slog.Logger
+	poolCfg PoolConfig
+}
+
+func NewWorker(store *storage.Store, checker *checker.Checker, tracker *state.Tracker, logger *slog.Logger, poolCfg PoolConfig) *Worker {
+	return &Worker{
+		store:   store,
+		checker: checker,
+		tracker: tracker,
+		logger:  logger,
+		poolCfg: poolCfg,
+	}
+}
+
+// Run polls for jobs until ctx is cancelled. Jobs that are claimed but not
+// finished by then are put back on the queue before Run returns.
+func (w *Worker) Run(ctx context.Context) {
+	pool := NewPool(w.poolCfg)
+	defer pool.Close()
+
+	w.logger.Info("worker started",
+		"workers", w.poolCfg.Workers,
+		"per_host", w.poolCfg.PerHost,
+		"queue_depth", w.poolCfg.QueueDepth,
+	)
+
+	go w.reportStats(ctx, pool)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx, pool)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) poll(ctx context.Context, pool *Pool) {
+	if n, err := w.store.DeadLetterExpiredCheckJobs(ctx); err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("failed to dead-letter expired check jobs", "error", err)
+		}
+	} else if n > 0 {
+		w.logger.Warn("check jobs moved to dead letter after lease expiry", "count", n)
+	}
+
+	free := min(pool.Free(), maxClaimBatch)
+	if free <= 0 {
+		return
+	}
+
+	jobs, err := w.store.ClaimCheckJobs(ctx, storage.ClaimCheckJobsParams{
+		LeaseMarginSeconds: leaseMarginSeconds,
+		BatchSize:          int32(free),
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("failed to claim check jobs", "error", err)
+		}
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	ids := make([]int32, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.MonitorID
+	}
+	monitors, err := w.store.ListMonitorsByIDs(ctx, ids)
+	if err != nil {
+		w.logger.Error("failed to load monitors for check jobs", "error", err)
+		for _, job := range jobs {
+			w.release(ctx, job)
+		}
+		return
+	}
+	byID := make(map[int32]storage.Monitor, len(monitors))
+	for _, mon := range monitors {
+		byID[mon.ID] = mon
+	}
+
+	for _, job := range jobs {
+		mon, ok := byID[job.MonitorID]
+		if !ok || mon.Status != "active" || mon.Type == checker.TypeHeartbeat {
+			// Paused, or turned into a heartbeat, after the job was queued.
+			w.complete(ctx, job)
+			continue
+		}
+
+		if err := pool.Submit(host(mon), func() { w.execute(ctx, job, mon) }); err != nil {
+			w.logger.Warn("check dropped", "monitor_id", mon.ID, "error", err)
+			w.release(ctx, job)
+		}
+	}
+}
+
+func (w *Worker) execute(ctx context.Context, job storage.CheckJob, mon storage.Monitor) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.fail(ctx, job, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if ctx.Err() != nil {
+		w.release(ctx, job)
+		return
+	}
+
+	result, attempts := w.check(ctx, mon)
+	if ctx.Err() != nil {
+		// The check was cut short by shutdown; its result says nothing
+		// about the monitor.
+		w.release(ctx, job)
+		return
+	}
+
+	if err := w.record(ctx, mon, result, attempts); err != nil {
+		w.fail(ctx, job, err)
+		return
+	}
+	w.complete(ctx, job)
+}
+
+// check runs mon's check, retrying a failure up to mon.Retries times so a
+// transient error does not produce a failed check. It returns the last
+// result and the number of attempts made.
+func (w *Worker) check(ctx context.Context, mon storage.Monitor) (checker.CheckResult, int) {
+	result := w.checker.Check(ctx, mon)
+	attempts := 1
+
+	for ; attempts <= int(mon.Retries) && result.Status != "success"; attempts++ {
+		select {
+		case <-ctx.Done():
+			return result, attempts
+		case <-time.After(checkRetryDelay):
+		}
+
+		w.logger.Info("retrying failed check",
+			"monitor_id", mon.ID,
+			"attempt", attempts+1,
+			"error", result.ErrorMessage,
+		)
+		result = w.checker.Check(ctx, mon)
+	}
+	return result, attempts
+}
+
+func (w *Worker) record(ctx context.Context, mon storage.Monitor, result checker.CheckResult, attempts int) error {
+	params := storage.CreateMonitorCheckParams{
+		MonitorID:      mon.ID,
+		Status:         result.Status,
+		Attempts:       int32(attempts),
+		ResponseTimeMs: pgtype.Int4{Int32: int32(result.ResponseTimeMs), Valid: result.ResponseTimeMs > 0},
+		StatusCode:     pgtype.Int4{Int32: int32(result.StatusCode), Valid: result.StatusCode > 0},
+		ErrorMessage:   pgtype.Text{String: result.ErrorMessage, Valid: result.ErrorMessage != ""},
+	}
+	if t := result.Timings; t != nil {
+		params.DnsMs = pgtype.Int4{Int32: int32(t.DNSMs), Valid: true}
+		params.ConnectMs = pgtype.Int4{Int32: int32(t.ConnectMs), Valid: true}
+		params.TlsMs = pgtype.Int4{Int32: int32(t.TLSMs), Valid: true}
+		params.TtfbMs = pgtype.Int4{Int32: int32(t.TTFBMs), Valid: true}
+		params.TransferMs = pgtype.Int4{Int32: int32(t.TransferMs), Valid: true}
+	}
+
+	if result.Details != nil {
+		details, err := json.Marshal(result.Details)
+		if err != nil {
+			return fmt.Errorf("failed to encode check details: %w", err)
+		}
+		params.Details = details
+	}
+
+	check, err := w.store.CreateMonitorCheck(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to save monitor check: %w", err)
+	}
+
+	// The check is saved, so a state failure must not fail the job and
+	// record it twice.
+	if err := w.tracker.Record(ctx, mon, check); err != nil {
+		w.logger.Error("failed to record monitor state", "monitor_id", mon.ID, "error", err)
+	}
+
+	if len(result.Certificates) > 0 {
+		if err := w.recordCertificate(ctx, mon, result.Certificates); err != nil {
+			w.logger.Error("failed to save certificate", "monitor_id", mon.ID, "error", err)
+		}
+	}
+
+	if result.Status != "success" {
+		w.logger.Warn("monitor check failed",
+			"monitor_id", mon.ID,
+			"status_code", result.StatusCode,
+			"error", result.ErrorMessage,
+		)
+	} else if result.Warning != "" {
+		w.logger.Warn("monitor check warning", "monitor_id", mon.ID, "warning", result.Warning)
+	}
+	return nil
+}
+
+// recordCertificate stores the latest certificate chain seen for mon.
+func (w *Worker) recordCertificate(ctx context.Context, mon storage.Monitor, chain []checker.Certificate) error {
+	chainJSON, err := json.Marshal(chain)
+	if err != nil {
+		return err
+	}
+
+	leaf := chain[0]
+	return w.store.UpsertMonitorCertificate(ctx, storage.UpsertMonitorCertificateParams{
+		MonitorID: mon.ID,
+		Subject:   leaf.Subject,
+		Issuer:    leaf.Issuer,
+		Sans:      leaf.SANs,
+		NotBefore: pgtype.Timestamp{Time: leaf.NotBefore, Valid: true},
+		NotAfter:  pgtype.Timestamp{Time: leaf.NotAfter, Valid: true},
+		Chain:     chainJSON,
+	})
+}
+
+func (w *Worker) complete(ctx context.Context, job storage.CheckJob) {
+	if err := w.store.CompleteCheckJob(ctx, job.ID); err != nil {
+		w.logger.Error("failed to complete check job", "job_id", job.ID, "error", err)
+	}
+}
+
+// release puts a claimed job back on the queue without counting the attempt.
+func (w *Worker) release(ctx context.Context, job storage.CheckJob) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	if err := w.store.ReleaseCheckJob(ctx, job.ID); err != nil {
+		w.logger.Error("failed to release check job", "job_id", job.ID, "error", err)
+	}
+}
+
+// fail retries the job with exponential backoff, or moves it to the dead
+// letter state once it has used all its attempts.
+func (w *Worker) fail(ctx context.Context, job storage.CheckJob, jobErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	lastError := pgtype.Text{String: jobErr.Error(), Valid: true}
+
+	if job.Attempts >= job.MaxAttempts {
+		w.logger.Error("check job moved to dead letter",
+			"job_id", job.ID,
+			"monitor_id", job.MonitorID,
+			"attempts", job.Attempts,
+			"error", jobErr,
+		)
+		err := w.store.DeadLetterCheckJob(ctx, storage.DeadLetterCheckJobParams{
+			ID:        job.ID,
+			LastError: lastError,
+		})
+		if err != nil {
+			w.logger.Error("failed to dead-letter check job", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	delay := retryDelay(job.Attempts)
+	w.logger.Warn("check job failed, retrying",
+		"job_id", job.ID,
+		"monitor_id", job.MonitorID,
+		"attempts", job.Attempts,
+		"retry_in", delay,
+		"error", jobErr,
+	)
+	err := w.store.RetryCheckJob(ctx, storage.RetryCheckJobParams{
+		RetryInSeconds: int32(delay / time.Second),
+		LastError:      lastError,
+		ID:             job.ID,
+	})
+	if err != nil {
+		w.logger.Error("failed to retry check job", "job_id", job.ID, "error", err)
+	}
+}
+
+func (w *Worker) reportStats(ctx context.Context, pool *Pool) {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := pool.Stats()
+			attrs := []any{
+				"queued", stats.Queued,
+				"running", stats.Running,
+				"submitted", stats.Submitted,
+				"completed", stats.Completed,
+				"rejected", stats.Rejected,
+			}
+			if dead, err := w.store.CountDeadCheckJobs(ctx); err == nil {
+				attrs = append(attrs, "dead_jobs", dead)
+			}
+			w.logger.Info("worker pool stats", attrs...)
+		}
+	}
+}
+
+// retryDelay doubles from retryBaseDelay with every attempt, up to
+// retryMaxDelay.
+func retryDelay(attempts int32) time.Duration {
+	delay := retryBaseDelay
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// host is the key used for per-host concurrency limits.
+func host(mon storage.Monitor) string {
+	switch mon.Type {
+	case checker.TypeTCP, checker.TypeGRPC, checker.TypePostgres, checker.TypeMySQL:
+		if h, _, err := net.SplitHostPort(mon.Url); err == nil {
+			return h
+		}
+	}
+	if u, err := url.Parse(mon.Url); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return mon.Url
+}
@@ -0,0 +1,72 @@
+// Package secret encrypts values, such as database DSNs, that monitors need
+// at check time but that must never be stored or returned in plain text.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoKey     = errors.New("secret key is not configured")
+	ErrMalformed = errors.New("malformed secret")
+)
+
+// Box seals and opens secrets with AES-256-GCM. A nil Box has no key and
+// refuses to do either.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New returns a Box keyed from key. It returns a nil Box when key is empty.
+func New(key string) (*Box, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	// Hashing lets any passphrase serve as a 256-bit key
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext. The random nonce is prepended to the result.
+func (b *Box) Seal(plaintext []byte) ([]byte, error) {
+	if b == nil {
+		return nil, ErrNoKey
+	}
+
+	nonce := make([]byte, b.aead.NonceSize(), b.aead.NonceSize()+len(plaintext)+b.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a value produced by Seal.
+func (b *Box) Open(sealed []byte) ([]byte, error) {
+	if b == nil {
+		return nil, ErrNoKey
+	}
+
+	size := b.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, ErrMalformed
+	}
+	plaintext, err := b.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,744 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/alert"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+// errUnknownChannel is returned when linking a monitor or escalation level
+// to a channel the user does not have.
+var errUnknownChannel = errors.New("unknown alert channel")
+
+type alertChannelRequest struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config"`
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled"`
+}
+
+func (r alertChannelRequest) Valid() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if r.Type == "" {
+		return errors.New("type is required")
+	}
+
+	return nil
+}
+
+type alertChannelResponse struct {
+	ID        int32           `json:"id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	Config    json.RawMessage `json:"config"`
+	Enabled   bool            `json:"enabled"`
+	HasSecret bool            `json:"has_secret,omitempty"`
+	// Secret is only returned when it is generated
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func toAlertChannelResponse(ch storage.AlertChannel) alertChannelResponse {
+	return alertChannelResponse{
+		ID:        ch.ID,
+		Name:      ch.Name,
+		Type:      ch.Type,
+		Config:    redactCredential(ch),
+		Enabled:   ch.Enabled,
+		HasSecret: len(ch.Secret) > 0,
+		CreatedAt: ch.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: ch.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// toAlertMonitorResponse renders mon as webhooks see it: its
+// monitorResponse, less anything that may carry a credential, such as the
+// ping token, headers, body, settings, proxy URL or DSN.
+func toAlertMonitorResponse(mon storage.Monitor) (monitorResponse, error) {
+	mon.PingToken = pgtype.Text{}
+	mon.Headers = nil
+	mon.Body = pgtype.Text{}
+	mon.Settings = nil
+	mon.ProxyUrl = pgtype.Text{}
+	mon.Secret = nil
+
+	resp, err := toMonitorResponse(mon)
+	if err != nil {
+		return resp, err
+	}
+
+	// Keep a password in the url out of the payload
+	if u, err := url.Parse(resp.Url); err == nil && u.User != nil {
+		resp.Url = u.Redacted()
+	}
+
+	return resp, nil
+}
+
+// redactCredential returns the config of ch with its credential, if its type
+// has one, replaced by alert.CredentialRedacted. Whatever the config holds
+// under the credential's field is never echoed.
+func redactCredential(ch storage.AlertChannel) json.RawMessage {
+	field, ok := alert.CredentialField(ch.Type)
+	if !ok {
+		return ch.Config
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ch.Config, &fields); err != nil || fields == nil {
+		// Never echo a config the credential could not be found in
+		return json.RawMessage("{}")
+	}
+
+	delete(fields, field)
+	if len(ch.Secret) > 0 {
+		fields[field], _ = json.Marshal(alert.CredentialRedacted)
+	}
+
+	config, err := json.Marshal(fields)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return config
+}
+
+// RenderAlertEvent renders a monitor and the check that changed its state
+// for alert.WebhookNotifier.
+func RenderAlertEvent(mon storage.Monitor, check *storage.MonitorCheck) (any, any, error) {
+	monResp, err := toAlertMonitorResponse(mon)
+	if err != nil {
+		return nil, nil, err
+	}
+	if check == nil {
+		return monResp, nil, nil
+	}
+	return monResp, toMonitorCheckResponse(*check), nil
+}
+
+type alertDeliveryResponse struct {
+	ID          int64                          `json:"id"`
+	ChannelID   int32                          `json:"channel_id"`
+	MonitorID   int32                          `json:"monitor_id"`
+	CheckID     int32                          `json:"check_id,omitempty"`
+	Event       string                         `json:"event"`
+	Status      string                         `json:"status"`
+	Attempts    int32                          `json:"attempts"`
+	MaxAttempts int32                          `json:"max_attempts"`
+	NextAttempt string                         `json:"next_attempt_at,omitempty"`
+	LastError   string                         `json:"last_error,omitempty"`
+	DeliveredAt string                         `json:"delivered_at,omitempty"`
+	AttemptLog  []alertDeliveryAttemptResponse `json:"attempt_log,omitempty"`
+	CreatedAt   string                         `json:"created_at"`
+	UpdatedAt   string                         `json:"updated_at"`
+}
+
+func toAlertDeliveryResponse(d storage.AlertDelivery) alertDeliveryResponse {
+	resp := alertDeliveryResponse{
+		ID:          d.ID,
+		ChannelID:   d.ChannelID.Int32,
+		MonitorID:   d.MonitorID,
+		CheckID:     d.CheckID.Int32,
+		Event:       d.Event,
+		Status:      d.Status,
+		Attempts:    d.Attempts,
+		MaxAttempts: d.MaxAttempts,
+		LastError:   d.LastError.String,
+		CreatedAt:   d.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:   d.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if d.Status == alert.StatusPending {
+		resp.NextAttempt = d.RunAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if d.DeliveredAt.Valid {
+		resp.DeliveredAt = d.DeliveredAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return resp
+}
+
+type alertDeliveryAttemptResponse struct {
+	Attempt     int32  `json:"attempt"`
+	Success     bool   `json:"success"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int32  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+}
+
+type monitorAlertChannelsRequest struct {
+	ChannelIDs []int32 `json:"channel_ids"`
+}
+
+func (r monitorAlertChannelsRequest) Valid() error {
+	return nil
+}
+
+// alertChannelParams checks req against the Notifier for its type and
+// returns its config, credential and enabled flag ready to store. A type's
+// credential is taken out of the config, to be sealed; when it is left out
+// or redacted, stored is used instead.
+func (s *Server) alertChannelParams(req alertChannelRequest, stored string) ([]byte, string, bool, error) {
+	n, ok := s.alerts.Notifier(req.Type)
+	if !ok {
+		return nil, "", false, fmt.Errorf("type must be one of: %s", strings.Join(s.alerts.Types(), ", "))
+	}
+
+	config := []byte(req.Config)
+	if len(config) == 0 || string(config) == "null" {
+		config = []byte("{}")
+	}
+
+	field, hasCredential := alert.CredentialField(req.Type)
+	var fields map[string]json.RawMessage
+	var credential string
+	if hasCredential {
+		if err := json.Unmarshal(config, &fields); err != nil || fields == nil {
+			return nil, "", false, errors.New("invalid config: must be an object")
+		}
+		if raw, ok := fields[field]; ok {
+			if err := json.Unmarshal(raw, &credential); err != nil {
+				return nil, "", false, fmt.Errorf("invalid config: %s must be a string", field)
+			}
+		}
+
+		if (credential == "" || credential == alert.CredentialRedacted) && stored != "" {
+			credential = stored
+			fields[field], _ = json.Marshal(credential)
+			var err error
+			if config, err = json.Marshal(fields); err != nil {
+				return nil, "", false, err
+			}
+		}
+		if credential == alert.CredentialRedacted {
+			return nil, "", false, fmt.Errorf("invalid config: %s is required", field)
+		}
+	}
+
+	if err := n.Validate(config); err != nil {
+		return nil, "", false, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if hasCredential {
+		delete(fields, field)
+		var err error
+		if config, err = json.Marshal(fields); err != nil {
+			return nil, "", false, err
+		}
+	}
+
+	enabled := req.Enabled == nil || *req.Enabled
+	return config, credential, enabled, nil
+}
+
+// storedCredential opens the sealed credential of ch, if its type has one
+// and it is stored.
+func (s *Server) storedCredential(ch storage.AlertChannel) (string, error) {
+	if _, ok := alert.CredentialField(ch.Type); !ok || len(ch.Secret) == 0 {
+		return "", nil
+	}
+
+	plain, err := s.secrets.Open(ch.Secret)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// newChannelSecret generates a signing secret for a channel of type
+// channelType, returning it along with its sealed form. Types that do not
+// sign have no secret.
+func (s *Server) newChannelSecret(channelType string) (string, []byte, error) {
+	if channelType != alert.TypeWebhook {
+		return "", nil, nil
+	}
+
+	plain, err := alert.NewWebhookSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	sealed, err := s.secrets.Seal([]byte(plain))
+	if err != nil {
+		return "", nil, err
+	}
+	return plain, sealed, nil
+}
+
+// CreateAlertChannel
+func (s *Server) handleCreateAlertChannel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(int)
+
+		req, err := decodeValid[alertChannelRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		config, credential, enabled, err := s.alertChannelParams(req, "")
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		plainSecret, sealedSecret, err := s.newChannelSecret(req.Type)
+		if err != nil {
+			respondSecretError(w, r, err)
+			return
+		}
+		if credential != "" {
+			sealedSecret, err = s.secrets.Seal([]byte(credential))
+			if err != nil {
+				respondSecretError(w, r, err)
+				return
+			}
+		}
+
+		ch, err := s.store.CreateAlertChannel(r.Context(), storage.CreateAlertChannelParams{
+			UserID:  int32(userID),
+			Name:    req.Name,
+			Type:    req.Type,
+			Config:  config,
+			Enabled: enabled,
+			Secret:  sealedSecret,
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := toAlertChannelResponse(ch)
+		resp.Secret = plainSecret
+		respond(w, r, http.StatusCreated, resp)
+	})
+}
+
+// ListAlertChannels
+func (s *Server) handleListAlertChannels() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(int)
+
+		channels, err := s.store.ListAlertChannelsByUser(r.Context(), int32(userID))
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		responses := make([]alertChannelResponse, 0, len(channels))
+		for _, ch := range channels {
+			responses = append(responses, toAlertChannelResponse(ch))
+		}
+
+		respondJSON(w, r, responses)
+	})
+}
+
+// GetAlertChannel
+func (s *Server) handleGetAlertChannel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := s.userAlertChannel(w, r)
+		if !ok {
+			return
+		}
+
+		respondJSON(w, r, toAlertChannelResponse(ch))
+	})
+}
+
+// UpdateAlertChannel
+func (s *Server) handleUpdateAlertChannel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur, ok := s.userAlertChannel(w, r)
+		if !ok {
+			return
+		}
+
+		req, err := decodeValid[alertChannelRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// A credential is only kept while the type stays the same
+		var stored string
+		if req.Type == cur.Type {
+			stored, err = s.storedCredential(cur)
+			if err != nil {
+				respondSecretError(w, r, err)
+				return
+			}
+		}
+
+		config, credential, enabled, err := s.alertChannelParams(req, stored)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// A channel keeps its signing secret; one changed to a signing type
+		// gets one
+		var plainSecret string
+		var sealedSecret []byte
+		switch {
+		case credential != "":
+			sealedSecret, err = s.secrets.Seal([]byte(credential))
+		case req.Type != cur.Type || len(cur.Secret) == 0:
+			plainSecret, sealedSecret, err = s.newChannelSecret(req.Type)
+		}
+		if err != nil {
+			respondSecretError(w, r, err)
+			return
+		}
+
+		ch, err := s.store.UpdateAlertChannel(r.Context(), storage.UpdateAlertChannelParams{
+			ID:      cur.ID,
+			UserID:  cur.UserID,
+			Name:    req.Name,
+			Type:    req.Type,
+			Config:  config,
+			Enabled: enabled,
+			Secret:  sealedSecret,
+		})
+		if err != nil {
+			if isNotFound(err) {
+				respondError(w, r, http.StatusNotFound, ErrNotFound)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := toAlertChannelResponse(ch)
+		resp.Secret = plainSecret
+		respondJSON(w, r, resp)
+	})
+}
+
+// RotateAlertChannelSecret replaces the signing secret of a webhook channel
+func (s *Server) handleRotateAlertChannelSecret() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur, ok := s.userAlertChannel(w, r)
+		if !ok {
+			return
+		}
+
+		if cur.Type != alert.TypeWebhook {
+			respondError(w, r, http.StatusBadRequest, errors.New("only webhook channels have a secret"))
+			return
+		}
+
+		plainSecret, sealedSecret, err := s.newChannelSecret(cur.Type)
+		if err != nil {
+			respondSecretError(w, r, err)
+			return
+		}
+
+		ch, err := s.store.UpdateAlertChannel(r.Context(), storage.UpdateAlertChannelParams{
+			ID:      cur.ID,
+			UserID:  cur.UserID,
+			Name:    cur.Name,
+			Type:    cur.Type,
+			Config:  cur.Config,
+			Enabled: cur.Enabled,
+			Secret:  sealedSecret,
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := toAlertChannelResponse(ch)
+		resp.Secret = plainSecret
+		respondJSON(w, r, resp)
+	})
+}
+
+// DeleteAlertChannel
+func (s *Server) handleDeleteAlertChannel() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		n, err := s.store.DeleteAlertChannel(r.Context(), storage.DeleteAlertChannelParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if n == 0 {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		noContent(w, r)
+	})
+}
+
+// ListAlertDeliveries, newest first, optionally filtered by status and
+// monitor
+func (s *Server) handleListAlertDeliveries() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := s.userAlertChannel(w, r)
+		if !ok {
+			return
+		}
+
+		limit, offset, err := pagination(r, defaultDeliveriesLimit, maxDeliveriesLimit)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		params := storage.ListAlertDeliveriesByChannelParams{
+			ChannelID: ch.ID,
+			Limit:     limit,
+			Offset:    offset,
+		}
+
+		query := r.URL.Query()
+
+		switch st := query.Get("status"); st {
+		case "":
+		case alert.StatusPending, alert.StatusSending, alert.StatusDelivered, alert.StatusFailed:
+			params.Status = pgtype.Text{String: st, Valid: true}
+		default:
+			respondError(w, r, http.StatusBadRequest, errors.New("status must be pending, sending, delivered or failed"))
+			return
+		}
+
+		if v := query.Get("monitor_id"); v != "" {
+			monitorID, err := strconv.Atoi(v)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("monitor_id must be an integer"))
+				return
+			}
+			params.MonitorID = pgtype.Int4{Int32: int32(monitorID), Valid: true}
+		}
+
+		deliveries, err := s.store.ListAlertDeliveriesByChannel(r.Context(), params)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		responses := make([]alertDeliveryResponse, 0, len(deliveries))
+		for _, d := range deliveries {
+			responses = append(responses, toAlertDeliveryResponse(d))
+		}
+
+		respondJSON(w, r, responses)
+	})
+}
+
+// GetAlertDelivery, with every attempt made to send it
+func (s *Server) handleGetAlertDelivery() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := s.userAlertChannel(w, r)
+		if !ok {
+			return
+		}
+
+		deliveryID, err := strconv.ParseInt(r.PathValue("deliveryID"), 10, 64)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		ctx := r.Context()
+
+		d, err := s.store.GetAlertDelivery(ctx, storage.GetAlertDeliveryParams{
+			ID:        deliveryID,
+			ChannelID: ch.ID,
+		})
+		if err != nil {
+			if isNotFound(err) {
+				respondError(w, r, http.StatusNotFound, ErrNotFound)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		attempts, err := s.store.ListAlertDeliveryAttempts(ctx, d.ID)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := toAlertDeliveryResponse(d)
+		for _, a := range attempts {
+			resp.AttemptLog = append(resp.AttemptLog, alertDeliveryAttemptResponse{
+				Attempt:     a.Attempt,
+				Success:     a.Success,
+				Error:       a.Error.String,
+				DurationMs:  a.DurationMs,
+				AttemptedAt: a.AttemptedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			})
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
+
+// GetMonitorAlertChannels
+func (s *Server) handleGetMonitorAlertChannels() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		monitorID, ok := s.userMonitorID(w, r)
+		if !ok {
+			return
+		}
+
+		s.respondMonitorAlertChannels(w, r, monitorID)
+	})
+}
+
+// SetMonitorAlertChannels replaces the channels linked to a monitor
+func (s *Server) handleSetMonitorAlertChannels() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		monitorID, ok := s.userMonitorID(w, r)
+		if !ok {
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		req, err := decodeValid[monitorAlertChannelsRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ids := slices.Clone(req.ChannelIDs)
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+
+		ctx := r.Context()
+		err = s.store.ExecTx(ctx, func(q *storage.Queries) error {
+			if err := q.ClearMonitorAlertChannels(ctx, monitorID); err != nil {
+				return err
+			}
+
+			if len(ids) == 0 {
+				return nil
+			}
+
+			n, err := q.LinkMonitorAlertChannels(ctx, storage.LinkMonitorAlertChannelsParams{
+				MonitorID:  monitorID,
+				ChannelIds: ids,
+				UserID:     int32(userID),
+			})
+			if err != nil {
+				return err
+			}
+			if n != int64(len(ids)) {
+				return errUnknownChannel
+			}
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, errUnknownChannel) {
+				respondError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		s.respondMonitorAlertChannels(w, r, monitorID)
+	})
+}
+
+func (s *Server) respondMonitorAlertChannels(w http.ResponseWriter, r *http.Request, monitorID int32) {
+	channels, err := s.store.ListMonitorAlertChannels(r.Context(), monitorID)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	responses := make([]alertChannelResponse, 0, len(channels))
+	for _, ch := range channels {
+		responses = append(responses, toAlertChannelResponse(ch))
+	}
+
+	respondJSON(w, r, responses)
+}
+
+// userAlertChannel looks up the alert channel named in the path, if it
+// belongs to the authenticated user.
+func (s *Server) userAlertChannel(w http.ResponseWriter, r *http.Request) (storage.AlertChannel, bool) {
+	var ch storage.AlertChannel
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+		return ch, false
+	}
+
+	userID := r.Context().Value("userID").(int)
+
+	ch, err = s.store.GetAlertChannelByUser(r.Context(), storage.GetAlertChannelByUserParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return ch, false
+		}
+		respondError(w, r, http.StatusInternalServerError, err)
+		return ch, false
+	}
+	return ch, true
+}
+
+// userMonitorID returns the id of the monitor named in the path, if it
+// belongs to the authenticated user.
+func (s *Server) userMonitorID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+		return 0, false
+	}
+
+	userID := r.Context().Value("userID").(int)
+
+	owns, err := s.store.UserOwnsMonitor(r.Context(), storage.UserOwnsMonitorParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, err)
+		return 0, false
+	}
+	if !owns {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return 0, false
+	}
+	return int32(id), true
+}
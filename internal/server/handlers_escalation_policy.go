@@ -0,0 +1,428 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// maxEscalationLevels caps how many levels a policy can have.
+const maxEscalationLevels = 10
+
+type escalationLevelRequest struct {
+	// DelayMinutes is how long after the incident starts the level is
+	// notified
+	DelayMinutes int32   `json:"delay_minutes"`
+	ChannelIDs   []int32 `json:"channel_ids"`
+}
+
+type escalationPolicyRequest struct {
+	Name   string                   `json:"name"`
+	Levels []escalationLevelRequest `json:"levels"`
+}
+
+func (r escalationPolicyRequest) Valid() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if len(r.Levels) == 0 {
+		return errors.New("levels is required")
+	}
+
+	if len(r.Levels) > maxEscalationLevels {
+		return fmt.Errorf("a policy can have at most %d levels", maxEscalationLevels)
+	}
+
+	for i, l := range r.Levels {
+		if l.DelayMinutes < 0 {
+			return fmt.Errorf("level %d: delay_minutes must not be negative", i+1)
+		}
+
+		if i > 0 && l.DelayMinutes < r.Levels[i-1].DelayMinutes {
+			return fmt.Errorf("level %d: delay_minutes must not be less than the level before", i+1)
+		}
+
+		if len(l.ChannelIDs) == 0 {
+			return fmt.Errorf("level %d: channel_ids is required", i+1)
+		}
+	}
+
+	return nil
+}
+
+type escalationLevelResponse struct {
+	Level        int32   `json:"level"`
+	DelayMinutes int32   `json:"delay_minutes"`
+	ChannelIDs   []int32 `json:"channel_ids"`
+}
+
+type escalationPolicyResponse struct {
+	ID        int32                     `json:"id"`
+	Name      string                    `json:"name"`
+	Levels    []escalationLevelResponse `json:"levels"`
+	CreatedAt string                    `json:"created_at"`
+	UpdatedAt string                    `json:"updated_at"`
+}
+
+type monitorEscalationPolicyRequest struct {
+	// PolicyID detaches the monitor from its policy when null
+	PolicyID *int32 `json:"policy_id"`
+}
+
+func (r monitorEscalationPolicyRequest) Valid() error {
+	return nil
+}
+
+// CreateEscalationPolicy
+func (s *Server) handleCreateEscalationPolicy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(int)
+
+		req, err := decodeValid[escalationPolicyRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ctx := r.Context()
+
+		var policy storage.EscalationPolicy
+		err = s.store.ExecTx(ctx, func(q *storage.Queries) error {
+			var err error
+			policy, err = q.CreateEscalationPolicy(ctx, storage.CreateEscalationPolicyParams{
+				UserID: int32(userID),
+				Name:   req.Name,
+			})
+			if err != nil {
+				return err
+			}
+
+			return setEscalationLevels(ctx, q, policy, req.Levels)
+		})
+		if err != nil {
+			if errors.Is(err, errUnknownChannel) {
+				respondError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp, err := s.escalationPolicyResponse(ctx, policy)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respond(w, r, http.StatusCreated, resp)
+	})
+}
+
+// ListEscalationPolicies
+func (s *Server) handleListEscalationPolicies() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(int)
+
+		ctx := r.Context()
+
+		policies, err := s.store.ListEscalationPoliciesByUser(ctx, int32(userID))
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		responses := make([]escalationPolicyResponse, 0, len(policies))
+		for _, policy := range policies {
+			resp, err := s.escalationPolicyResponse(ctx, policy)
+			if err != nil {
+				respondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			responses = append(responses, resp)
+		}
+
+		respondJSON(w, r, responses)
+	})
+}
+
+// GetEscalationPolicy
+func (s *Server) handleGetEscalationPolicy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := s.userEscalationPolicy(w, r)
+		if !ok {
+			return
+		}
+
+		resp, err := s.escalationPolicyResponse(r.Context(), policy)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
+
+// UpdateEscalationPolicy replaces the name and levels of a policy. Incidents
+// already escalating carry on through the new levels.
+func (s *Server) handleUpdateEscalationPolicy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur, ok := s.userEscalationPolicy(w, r)
+		if !ok {
+			return
+		}
+
+		req, err := decodeValid[escalationPolicyRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ctx := r.Context()
+
+		var policy storage.EscalationPolicy
+		err = s.store.ExecTx(ctx, func(q *storage.Queries) error {
+			var err error
+			policy, err = q.UpdateEscalationPolicy(ctx, storage.UpdateEscalationPolicyParams{
+				ID:     cur.ID,
+				UserID: cur.UserID,
+				Name:   req.Name,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := q.ClearEscalationLevels(ctx, policy.ID); err != nil {
+				return err
+			}
+
+			return setEscalationLevels(ctx, q, policy, req.Levels)
+		})
+		if err != nil {
+			if isNotFound(err) {
+				respondError(w, r, http.StatusNotFound, ErrNotFound)
+				return
+			}
+			if errors.Is(err, errUnknownChannel) {
+				respondError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp, err := s.escalationPolicyResponse(ctx, policy)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
+
+// DeleteEscalationPolicy, detaching it from its monitors
+func (s *Server) handleDeleteEscalationPolicy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		n, err := s.store.DeleteEscalationPolicy(r.Context(), storage.DeleteEscalationPolicyParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if n == 0 {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		noContent(w, r)
+	})
+}
+
+// SetMonitorEscalationPolicy attaches a monitor to a policy, or detaches it.
+// Incidents already open keep the policy they started with.
+func (s *Server) handleSetMonitorEscalationPolicy() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		req, err := decodeValid[monitorEscalationPolicyRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ctx := r.Context()
+
+		var policyID pgtype.Int4
+		if req.PolicyID != nil {
+			_, err := s.store.GetEscalationPolicyByUser(ctx, storage.GetEscalationPolicyByUserParams{
+				ID:     *req.PolicyID,
+				UserID: int32(userID),
+			})
+			if err != nil {
+				if isNotFound(err) {
+					respondError(w, r, http.StatusBadRequest, errors.New("unknown escalation policy"))
+					return
+				}
+				respondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			policyID = pgtype.Int4{Int32: *req.PolicyID, Valid: true}
+		}
+
+		n, err := s.store.SetMonitorEscalationPolicy(ctx, storage.SetMonitorEscalationPolicyParams{
+			PolicyID: policyID,
+			ID:       int32(id),
+			UserID:   int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if n == 0 {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		s.notifyMonitorChanged(ctx, int32(id))
+
+		mon, err := s.store.GetMonitor(ctx, int32(id))
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp, err := toMonitorResponse(mon)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
+
+// setEscalationLevels adds levels to policy in order, failing with
+// errUnknownChannel if any level names a channel the policy's owner does not
+// have.
+func setEscalationLevels(ctx context.Context, q *storage.Queries, policy storage.EscalationPolicy, levels []escalationLevelRequest) error {
+	for i, l := range levels {
+		level, err := q.CreateEscalationLevel(ctx, storage.CreateEscalationLevelParams{
+			PolicyID:     policy.ID,
+			Position:     int32(i),
+			DelayMinutes: l.DelayMinutes,
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := slices.Clone(l.ChannelIDs)
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+
+		n, err := q.LinkEscalationLevelChannels(ctx, storage.LinkEscalationLevelChannelsParams{
+			LevelID:    level.ID,
+			ChannelIds: ids,
+			UserID:     policy.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if n != int64(len(ids)) {
+			return errUnknownChannel
+		}
+	}
+	return nil
+}
+
+// escalationPolicyResponse renders policy with its levels.
+func (s *Server) escalationPolicyResponse(ctx context.Context, policy storage.EscalationPolicy) (escalationPolicyResponse, error) {
+	resp := escalationPolicyResponse{
+		ID:        policy.ID,
+		Name:      policy.Name,
+		CreatedAt: policy.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: policy.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	levels, err := s.store.ListEscalationLevels(ctx, policy.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	channels, err := s.store.ListEscalationLevelChannels(ctx, policy.ID)
+	if err != nil {
+		return resp, err
+	}
+
+	channelIDs := make(map[int32][]int32, len(levels))
+	for _, c := range channels {
+		channelIDs[c.LevelID] = append(channelIDs[c.LevelID], c.ChannelID)
+	}
+
+	resp.Levels = make([]escalationLevelResponse, 0, len(levels))
+	for _, l := range levels {
+		ids := channelIDs[l.ID]
+		if ids == nil {
+			// Every channel on the level has since been deleted
+			ids = []int32{}
+		}
+		resp.Levels = append(resp.Levels, escalationLevelResponse{
+			Level:        l.Position + 1,
+			DelayMinutes: l.DelayMinutes,
+			ChannelIDs:   ids,
+		})
+	}
+
+	return resp, nil
+}
+
+// userEscalationPolicy looks up the escalation policy named in the path, if
+// it belongs to the authenticated user.
+func (s *Server) userEscalationPolicy(w http.ResponseWriter, r *http.Request) (storage.EscalationPolicy, bool) {
+	var policy storage.EscalationPolicy
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+		return policy, false
+	}
+
+	userID := r.Context().Value("userID").(int)
+
+	policy, err = s.store.GetEscalationPolicyByUser(r.Context(), storage.GetEscalationPolicyByUserParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return policy, false
+		}
+		respondError(w, r, http.StatusInternalServerError, err)
+		return policy, false
+	}
+	return policy, true
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/checker"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// newPingToken returns the secret that identifies a heartbeat monitor in its
+// ping URL.
+func newPingToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// pingMonitor looks up the active heartbeat monitor a ping is for. Pings for
+// paused monitors are accepted but ignored.
+func (s *Server) pingMonitor(w http.ResponseWriter, r *http.Request) (storage.Monitor, bool) {
+	mon, err := s.store.GetMonitorByPingToken(r.Context(), pgtype.Text{String: r.PathValue("token"), Valid: true})
+	if err != nil {
+		if isNotFound(err) {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return mon, false
+		}
+		respondError(w, r, http.StatusInternalServerError, err)
+		return mon, false
+	}
+
+	if mon.Status != "active" {
+		noContent(w, r)
+		return mon, false
+	}
+	return mon, true
+}
+
+// Ping start
+func (s *Server) handlePingStart() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mon, ok := s.pingMonitor(w, r)
+		if !ok {
+			return
+		}
+
+		if err := s.store.StartHeartbeat(r.Context(), mon.ID); err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		noContent(w, r)
+	})
+}
+
+// Ping success, or failure when the exit_code query parameter is non-zero
+func (s *Server) handlePing() http.Handler {
+	return s.handlePingResult(checker.HeartbeatSuccess)
+}
+
+// Ping failure
+func (s *Server) handlePingFail() http.Handler {
+	return s.handlePingResult(checker.HeartbeatFail)
+}
+
+func (s *Server) handlePingResult(event string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		details := checker.HeartbeatDetails{Event: event}
+
+		if v := r.URL.Query().Get("exit_code"); v != "" {
+			code, err := strconv.Atoi(v)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("exit_code must be an integer"))
+				return
+			}
+			details.ExitCode = &code
+			if code != 0 {
+				details.Event = checker.HeartbeatFail
+			}
+		}
+
+		mon, ok := s.pingMonitor(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		durationMs, err := s.store.RecordHeartbeatPing(ctx, mon.ID)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		params := storage.CreateMonitorCheckParams{
+			MonitorID: mon.ID,
+			Status:    "success",
+			Attempts:  1,
+		}
+
+		// A start ping lets us report how long the job ran
+		if durationMs.Valid {
+			details.DurationMs = &durationMs.Int64
+			params.ResponseTimeMs = pgtype.Int4{Int32: int32(durationMs.Int64), Valid: true}
+		}
+
+		if details.Event == checker.HeartbeatFail {
+			params.Status = "failed"
+			msg := "Job reported failure"
+			if details.ExitCode != nil {
+				msg = fmt.Sprintf("Job exited with code %d", *details.ExitCode)
+			}
+			params.ErrorMessage = pgtype.Text{String: msg, Valid: true}
+		}
+
+		detailsJSON, err := json.Marshal(details)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		params.Details = detailsJSON
+
+		check, err := s.store.CreateMonitorCheck(ctx, params)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// The ping has been recorded, so a state failure is only logged
+		if err := s.tracker.Record(ctx, mon, check); err != nil {
+			s.logger.Error("failed to record monitor state", "monitor_id", mon.ID, "error", err)
+		}
+
+		noContent(w, r)
+	})
+}
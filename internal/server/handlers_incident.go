@@ -0,0 +1,259 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/incident"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	defaultIncidentsLimit = 50
+	maxIncidentsLimit     = 500
+)
+
+type incidentResponse struct {
+	ID              int32                  `json:"id"`
+	MonitorID       int32                  `json:"monitor_id"`
+	State           string                 `json:"state"`
+	Cause           string                 `json:"cause,omitempty"`
+	StartedAt       string                 `json:"started_at"`
+	ResolvedAt      string                 `json:"resolved_at,omitempty"`
+	DurationSeconds *int32                 `json:"duration_seconds,omitempty"`
+	AcknowledgedAt  string                 `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy  int32                  `json:"acknowledged_by,omitempty"`
+	RootCause       string                 `json:"root_cause,omitempty"`
+	Resolution      string                 `json:"resolution,omitempty"`
+	Postmortem      string                 `json:"postmortem,omitempty"`
+	Checks          []monitorCheckResponse `json:"checks,omitempty"`
+	Escalation      *escalationResponse    `json:"escalation,omitempty"`
+	CreatedAt       string                 `json:"created_at"`
+	UpdatedAt       string                 `json:"updated_at"`
+}
+
+type escalationResponse struct {
+	PolicyID       int32  `json:"policy_id"`
+	LevelsNotified int32  `json:"levels_notified"`
+	NextLevelAt    string `json:"next_level_at,omitempty"`
+	StoppedAt      string `json:"stopped_at,omitempty"`
+	StopReason     string `json:"stop_reason,omitempty"`
+}
+
+func toEscalationResponse(esc storage.Escalation) *escalationResponse {
+	resp := &escalationResponse{
+		PolicyID:       esc.PolicyID,
+		LevelsNotified: esc.NextLevel,
+		StopReason:     esc.StopReason.String,
+	}
+
+	if esc.StoppedAt.Valid {
+		resp.StoppedAt = esc.StoppedAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	} else {
+		resp.NextLevelAt = esc.NextAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return resp
+}
+
+func toIncidentResponse(inc storage.Incident) incidentResponse {
+	resp := incidentResponse{
+		ID:             inc.ID,
+		MonitorID:      inc.MonitorID,
+		State:          inc.State,
+		Cause:          inc.Cause.String,
+		StartedAt:      inc.StartedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		AcknowledgedBy: inc.AcknowledgedBy.Int32,
+		RootCause:      inc.RootCause.String,
+		Resolution:     inc.Resolution.String,
+		Postmortem:     inc.Postmortem.String,
+		CreatedAt:      inc.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      inc.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if inc.ResolvedAt.Valid {
+		resp.ResolvedAt = inc.ResolvedAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if inc.DurationSeconds.Valid {
+		resp.DurationSeconds = &inc.DurationSeconds.Int32
+	}
+
+	if inc.AcknowledgedAt.Valid {
+		resp.AcknowledgedAt = inc.AcknowledgedAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return resp
+}
+
+// incidentFilter reads the state, since, until, limit and offset query
+// parameters. since and until bound the incident start time and are RFC 3339
+// timestamps.
+func incidentFilter(r *http.Request, userID int) (storage.ListIncidentsByUserParams, error) {
+	params := storage.ListIncidentsByUserParams{UserID: int32(userID)}
+
+	limit, offset, err := pagination(r, defaultIncidentsLimit, maxIncidentsLimit)
+	if err != nil {
+		return params, err
+	}
+	params.Limit = limit
+	params.Offset = offset
+
+	query := r.URL.Query()
+
+	switch st := query.Get("state"); st {
+	case "":
+	case incident.Open, incident.Resolved:
+		params.State = pgtype.Text{String: st, Valid: true}
+	default:
+		return params, errors.New("state must be open or resolved")
+	}
+
+	if v := query.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return params, errors.New("since must be an RFC 3339 timestamp")
+		}
+		params.Since = pgtype.Timestamp{Time: t.UTC(), Valid: true}
+	}
+
+	if v := query.Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return params, errors.New("until must be an RFC 3339 timestamp")
+		}
+		params.Until = pgtype.Timestamp{Time: t.UTC(), Valid: true}
+	}
+
+	return params, nil
+}
+
+// ListIncidents
+func (s *Server) handleListIncidents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value("userID").(int)
+
+		params, err := incidentFilter(r, userID)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		s.listIncidents(w, r, params)
+	})
+}
+
+// ListMonitorIncidents
+func (s *Server) handleListMonitorIncidents() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		params, err := incidentFilter(r, userID)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		owns, err := s.store.UserOwnsMonitor(r.Context(), storage.UserOwnsMonitorParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !owns {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		params.MonitorID = pgtype.Int4{Int32: int32(id), Valid: true}
+		s.listIncidents(w, r, params)
+	})
+}
+
+func (s *Server) listIncidents(w http.ResponseWriter, r *http.Request, params storage.ListIncidentsByUserParams) {
+	incidents, err := s.store.ListIncidentsByUser(r.Context(), params)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	responses := make([]incidentResponse, 0, len(incidents))
+	for _, inc := range incidents {
+		responses = append(responses, toIncidentResponse(inc))
+	}
+
+	respondJSON(w, r, responses)
+}
+
+// GetIncident, with the checks that triggered it and how far it has
+// escalated
+func (s *Server) handleGetIncident() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inc, ok := s.userIncident(w, r)
+		if !ok {
+			return
+		}
+		ctx := r.Context()
+
+		checks, err := s.store.ListIncidentChecks(ctx, inc.ID)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := toIncidentResponse(inc)
+		for _, c := range checks {
+			resp.Checks = append(resp.Checks, toMonitorCheckResponse(c))
+		}
+
+		esc, err := s.store.GetEscalation(ctx, inc.ID)
+		if err != nil && !isNotFound(err) {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if err == nil {
+			resp.Escalation = toEscalationResponse(esc)
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
+
+// userIncident looks up the incident named in the path, if it belongs to a
+// monitor of the authenticated user.
+func (s *Server) userIncident(w http.ResponseWriter, r *http.Request) (storage.Incident, bool) {
+	var inc storage.Incident
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+		return inc, false
+	}
+
+	userID := r.Context().Value("userID").(int)
+
+	inc, err = s.store.GetIncidentByUser(r.Context(), storage.GetIncidentByUserParams{
+		ID:     int32(id),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return inc, false
+		}
+		respondError(w, r, http.StatusInternalServerError, err)
+		return inc, false
+	}
+	return inc, true
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/incident"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// maxNoteLength caps the length of an incident note.
+const maxNoteLength = 10000
+
+type incidentEventResponse struct {
+	ID         int32  `json:"id"`
+	IncidentID int32  `json:"incident_id"`
+	Type       string `json:"type"`
+	Message    string `json:"message,omitempty"`
+	UserID     int32  `json:"user_id,omitempty"`
+	UserName   string `json:"user_name,omitempty"`
+	CheckID    int32  `json:"check_id,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+func toIncidentEventResponse(event storage.IncidentEvent) incidentEventResponse {
+	return incidentEventResponse{
+		ID:         event.ID,
+		IncidentID: event.IncidentID,
+		Type:       event.Type,
+		Message:    event.Message.String,
+		UserID:     event.UserID.Int32,
+		CheckID:    event.CheckID.Int32,
+		OccurredAt: event.OccurredAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+type addIncidentNoteRequest struct {
+	Message string `json:"message"`
+}
+
+func (r addIncidentNoteRequest) Valid() error {
+	if r.Message == "" {
+		return errors.New("message is required")
+	}
+
+	if len(r.Message) > maxNoteLength {
+		return errors.New("message is too long")
+	}
+
+	return nil
+}
+
+type updateIncidentPostmortemRequest struct {
+	RootCause  string `json:"root_cause"`
+	Resolution string `json:"resolution"`
+	Postmortem string `json:"postmortem"`
+}
+
+func (r updateIncidentPostmortemRequest) Valid() error {
+	return nil
+}
+
+// GetIncidentTimeline, oldest event first
+func (s *Server) handleGetIncidentTimeline() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inc, ok := s.userIncident(w, r)
+		if !ok {
+			return
+		}
+
+		events, err := s.store.ListIncidentEvents(r.Context(), inc.ID)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		responses := make([]incidentEventResponse, 0, len(events))
+		for _, e := range events {
+			resp := toIncidentEventResponse(storage.IncidentEvent{
+				ID:         e.ID,
+				IncidentID: e.IncidentID,
+				Type:       e.Type,
+				UserID:     e.UserID,
+				CheckID:    e.CheckID,
+				Message:    e.Message,
+				OccurredAt: e.OccurredAt,
+			})
+			resp.UserName = e.UserName.String
+			responses = append(responses, resp)
+		}
+
+		respondJSON(w, r, responses)
+	})
+}
+
+// AcknowledgeIncident
+func (s *Server) handleAcknowledgeIncident() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inc, ok := s.userIncident(w, r)
+		if !ok {
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		inc, err := s.incidents.Acknowledge(r.Context(), inc, int32(userID))
+		if err != nil {
+			if errors.Is(err, incident.ErrAcknowledged) {
+				respondError(w, r, http.StatusConflict, err)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondJSON(w, r, toIncidentResponse(inc))
+	})
+}
+
+// AddIncidentNote
+func (s *Server) handleAddIncidentNote() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inc, ok := s.userIncident(w, r)
+		if !ok {
+			return
+		}
+
+		req, err := decodeValid[addIncidentNoteRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		event, err := s.incidents.AddNote(r.Context(), inc.ID, int32(userID), req.Message)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respond(w, r, http.StatusCreated, toIncidentEventResponse(event))
+	})
+}
+
+// UpdateIncidentPostmortem
+func (s *Server) handleUpdateIncidentPostmortem() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inc, ok := s.userIncident(w, r)
+		if !ok {
+			return
+		}
+
+		req, err := decodeValid[updateIncidentPostmortemRequest](r)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+
+		inc, err = s.incidents.UpdatePostmortem(r.Context(), storage.UpdateIncidentPostmortemParams{
+			ID:         inc.ID,
+			RootCause:  pgtype.Text{String: req.RootCause, Valid: req.RootCause != ""},
+			Resolution: pgtype.Text{String: req.Resolution, Valid: req.Resolution != ""},
+			Postmortem: pgtype.Text{String: req.Postmortem, Valid: req.Postmortem != ""},
+		}, int32(userID))
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		respondJSON(w, r, toIncidentResponse(inc))
+	})
+}
@@ -1,55 +1,114 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rammyblog/monitor-bee/internal/checker"
 	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
 )
 
+// defaultCertExpiryThresholdDays is how close to expiry a certificate may get
+// before checks warn or fail, when the monitor does not say.
+const defaultCertExpiryThresholdDays = 14
+
+// maxRetries caps how many times a failed check is retried before it is
+// recorded.
+const maxRetries = 5
+
+// defaultGraceSeconds is how late a heartbeat ping may be before it counts
+// as missed, when the monitor does not say.
+const defaultGraceSeconds = 60
+
 type createMonitorRequest struct {
-	Name               string         `json:"name"`
-	Url                string         `json:"url"`
-	Method             string         `json:"method"`
-	IntervalSeconds    int16          `json:"interval_seconds"`
-	TimeoutSeconds     int16          `json:"timeout_seconds"`
-	Status             string         `json:"status"`
-	Headers            map[string]any `json:"headers"`
-	Body               string         `json:"body"`
-	ExpectedStatusCode int            `json:"expected_status_code"`
+	Name                    string              `json:"name"`
+	Url                     string              `json:"url"`
+	Method                  string              `json:"method"`
+	IntervalSeconds         int16               `json:"interval_seconds"`
+	TimeoutSeconds          int16               `json:"timeout_seconds"`
+	Status                  string              `json:"status"`
+	Headers                 map[string]any      `json:"headers"`
+	Body                    string              `json:"body"`
+	ExpectedStatusCode      int                 `json:"expected_status_code"`
+	SkipTlsVerify           bool                `json:"skip_tls_verify"`
+	ProxyUrl                string              `json:"proxy_url"`
+	FreshConnection         bool                `json:"fresh_connection"`
+	Assertions              []checker.Assertion `json:"assertions"`
+	CertExpiryThresholdDays int                 `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string              `json:"cert_expiry_action"`
+	Type                    string              `json:"type"`
+	Settings                map[string]any      `json:"settings"`
+	GraceSeconds            int                 `json:"grace_seconds"`
+	Dsn                     string              `json:"dsn"`
+	Retries                 int                 `json:"retries"`
+	FailureThreshold        int                 `json:"failure_threshold"`
+	RecoveryThreshold       int                 `json:"recovery_threshold"`
 }
 
 type monitorResponse struct {
-	ID                 int32          `json:"id"`
-	UserID             int32          `json:"user_id"`
-	Name               string         `json:"name"`
-	Url                string         `json:"url"`
-	Method             string         `json:"method"`
-	IntervalSeconds    int32          `json:"interval_seconds"`
-	TimeoutSeconds     int32          `json:"timeout_seconds"`
-	Status             string         `json:"status"`
-	Headers            map[string]any `json:"headers,omitempty"`
-	Body               string         `json:"body,omitempty"`
-	ExpectedStatusCode int            `json:"expected_status_code,omitempty"`
-	CreatedAt          string         `json:"created_at"`
-	UpdatedAt          string         `json:"updated_at"`
+	ID                      int32               `json:"id"`
+	UserID                  int32               `json:"user_id"`
+	Name                    string              `json:"name"`
+	Url                     string              `json:"url"`
+	Method                  string              `json:"method"`
+	IntervalSeconds         int32               `json:"interval_seconds"`
+	TimeoutSeconds          int32               `json:"timeout_seconds"`
+	Status                  string              `json:"status"`
+	Headers                 map[string]any      `json:"headers,omitempty"`
+	Body                    string              `json:"body,omitempty"`
+	ExpectedStatusCode      int                 `json:"expected_status_code,omitempty"`
+	SkipTlsVerify           bool                `json:"skip_tls_verify"`
+	ProxyUrl                string              `json:"proxy_url,omitempty"`
+	FreshConnection         bool                `json:"fresh_connection"`
+	Assertions              []checker.Assertion `json:"assertions,omitempty"`
+	CertExpiryThresholdDays int32               `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string              `json:"cert_expiry_action"`
+	Type                    string              `json:"type"`
+	Settings                map[string]any      `json:"settings,omitempty"`
+	PingToken               string              `json:"ping_token,omitempty"`
+	GraceSeconds            int32               `json:"grace_seconds"`
+	LastPingAt              string              `json:"last_ping_at,omitempty"`
+	HasDsn                  bool                `json:"has_dsn,omitempty"`
+	Retries                 int32               `json:"retries"`
+	FailureThreshold        int32               `json:"failure_threshold"`
+	RecoveryThreshold       int32               `json:"recovery_threshold"`
+	EscalationPolicyID      int32               `json:"escalation_policy_id,omitempty"`
+	CreatedAt               string              `json:"created_at"`
+	UpdatedAt               string              `json:"updated_at"`
 }
 
 func toMonitorResponse(mon storage.Monitor) (monitorResponse, error) {
 	resp := monitorResponse{
-		ID:              mon.ID,
-		UserID:          mon.UserID,
-		Name:            mon.Name,
-		Url:             mon.Url,
-		Method:          mon.Method,
-		IntervalSeconds: mon.IntervalSeconds,
-		TimeoutSeconds:  mon.TimeoutSeconds,
-		Status:          mon.Status,
-		CreatedAt:       mon.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:       mon.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		ID:                      mon.ID,
+		UserID:                  mon.UserID,
+		Name:                    mon.Name,
+		Url:                     mon.Url,
+		Method:                  mon.Method,
+		IntervalSeconds:         mon.IntervalSeconds,
+		TimeoutSeconds:          mon.TimeoutSeconds,
+		Status:                  mon.Status,
+		SkipTlsVerify:           mon.SkipTlsVerify,
+		ProxyUrl:                mon.ProxyUrl.String,
+		FreshConnection:         mon.FreshConnection,
+		CertExpiryThresholdDays: mon.CertExpiryThresholdDays,
+		CertExpiryAction:        mon.CertExpiryAction,
+		Type:                    mon.Type,
+		PingToken:               mon.PingToken.String,
+		GraceSeconds:            mon.GraceSeconds,
+		HasDsn:                  len(mon.Secret) > 0,
+		Retries:                 mon.Retries,
+		FailureThreshold:        mon.FailureThreshold,
+		RecoveryThreshold:       mon.RecoveryThreshold,
+		EscalationPolicyID:      mon.EscalationPolicyID.Int32,
+		CreatedAt:               mon.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:               mon.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	if len(mon.Headers) > 0 {
@@ -60,6 +119,22 @@ func toMonitorResponse(mon storage.Monitor) (monitorResponse, error) {
 		resp.Headers = headers
 	}
 
+	if len(mon.Assertions) > 0 {
+		if err := json.Unmarshal(mon.Assertions, &resp.Assertions); err != nil {
+			return resp, err
+		}
+	}
+
+	if mon.LastPingAt.Valid {
+		resp.LastPingAt = mon.LastPingAt.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	if len(mon.Settings) > 0 {
+		if err := json.Unmarshal(mon.Settings, &resp.Settings); err != nil {
+			return resp, err
+		}
+	}
+
 	if mon.Body.Valid {
 		resp.Body = mon.Body.String
 	}
@@ -76,12 +151,20 @@ func (r createMonitorRequest) Valid() error {
 		return errors.New("name is required")
 	}
 
-	if r.Url == "" {
+	if r.Url == "" && r.Type != checker.TypeHeartbeat {
 		return errors.New("url is required")
 	}
 
-	if r.Method == "" {
-		return errors.New("method is required")
+	if r.GraceSeconds < 0 {
+		return errors.New("grace_seconds must not be negative")
+	}
+
+	if err := validateMonitorType(r.Type, r.Url, r.Method, r.Dsn, r.Assertions, r.Settings); err != nil {
+		return err
+	}
+
+	if isDatabaseType(r.Type) && r.Dsn == "" {
+		return errors.New("dsn is required")
 	}
 
 	if r.Status == "" {
@@ -100,10 +183,173 @@ func (r createMonitorRequest) Valid() error {
 		return errors.New("timeout_seconds must be less than interval_seconds")
 	}
 
+	if err := validateRetries(r.Retries, r.FailureThreshold, r.RecoveryThreshold, r.TimeoutSeconds, r.IntervalSeconds); err != nil {
+		return err
+	}
+
+	if r.ProxyUrl != "" {
+		if _, err := url.Parse(r.ProxyUrl); err != nil {
+			return errors.New("proxy_url must be a valid url")
+		}
+	}
+
+	if err := checker.ValidateAssertions(r.Assertions); err != nil {
+		return err
+	}
+
+	if err := validateCertExpiry(r.CertExpiryThresholdDays, r.CertExpiryAction); err != nil {
+		return err
+	}
+
 	return nil
 
 }
 
+// validateMonitorType checks the fields whose meaning depends on the monitor
+// type: url is a URL for HTTP monitors, a host:port for TCP and gRPC
+// monitors, a name to resolve for DNS monitors and a ws:// or wss:// URL for
+// WebSocket monitors. Multi-step monitors describe their requests in settings.
+// Postgres and MySQL monitors take a host:port url for display and connect
+// with their dsn.
+func validateMonitorType(monitorType, target, method, dsn string, assertions []checker.Assertion, settings map[string]any) error {
+	if monitorType != "" && monitorType != checker.TypeHTTP && len(assertions) > 0 {
+		return errors.New("assertions are only supported for http monitors")
+	}
+
+	if dsn != "" && !isDatabaseType(monitorType) {
+		return errors.New("dsn is only supported for postgres and mysql monitors")
+	}
+
+	switch monitorType {
+	case "", checker.TypeHTTP:
+		if method == "" {
+			return errors.New("method is required")
+		}
+	case checker.TypeTCP, checker.TypeGRPC, checker.TypePostgres, checker.TypeMySQL:
+		if err := checker.ValidateTCPTarget(target); err != nil {
+			return fmt.Errorf("url must be a host:port for %s monitors", monitorType)
+		}
+		if isDatabaseType(monitorType) {
+			raw, err := json.Marshal(settings)
+			if err != nil {
+				return errors.New("invalid settings format")
+			}
+			if _, err := checker.ParseDatabaseSettings(raw); err != nil {
+				return fmt.Errorf("settings: %w", err)
+			}
+		}
+	case checker.TypeDNS:
+		if strings.ContainsAny(target, "/: ") {
+			return errors.New("url must be a host name for dns monitors")
+		}
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return errors.New("invalid settings format")
+		}
+		if _, err := checker.ParseDNSSettings(raw); err != nil {
+			return fmt.Errorf("settings: %w", err)
+		}
+	case checker.TypeWebSocket:
+		if err := checker.ValidateWebSocketURL(target); err != nil {
+			return errors.New("url must be a ws:// or wss:// url for websocket monitors")
+		}
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return errors.New("invalid settings format")
+		}
+		if _, err := checker.ParseWebSocketSettings(raw); err != nil {
+			return fmt.Errorf("settings: %w", err)
+		}
+	case checker.TypeMultiStep:
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return errors.New("invalid settings format")
+		}
+		if _, err := checker.ParseMultiStepSettings(raw); err != nil {
+			return fmt.Errorf("settings: %w", err)
+		}
+	case checker.TypeHeartbeat:
+	default:
+		return errors.New("type must be http, tcp, dns, grpc, websocket, multistep, heartbeat, postgres or mysql")
+	}
+	return nil
+}
+
+func isDatabaseType(t string) bool {
+	return t == checker.TypePostgres || t == checker.TypeMySQL
+}
+
+func monitorType(t string) string {
+	if t == "" {
+		return checker.TypeHTTP
+	}
+	return t
+}
+
+func graceSeconds(seconds int) int32 {
+	if seconds == 0 {
+		return defaultGraceSeconds
+	}
+	return int32(seconds)
+}
+
+// validateRetries checks the retry and state threshold settings. Every
+// attempt of a check must fit within the interval.
+func validateRetries(retries, failureThreshold, recoveryThreshold int, timeout, interval int16) error {
+	if retries < 0 || retries > maxRetries {
+		return fmt.Errorf("retries must be between 0 and %d", maxRetries)
+	}
+
+	if failureThreshold < 0 {
+		return errors.New("failure_threshold must not be negative")
+	}
+
+	if recoveryThreshold < 0 {
+		return errors.New("recovery_threshold must not be negative")
+	}
+
+	if int(timeout)*(retries+1) >= int(interval) {
+		return errors.New("timeout_seconds times (retries + 1) must be less than interval_seconds")
+	}
+
+	return nil
+}
+
+// threshold returns n, or 1 when it is not set.
+func threshold(n int) int32 {
+	if n == 0 {
+		return 1
+	}
+	return int32(n)
+}
+
+func validateCertExpiry(thresholdDays int, action string) error {
+	if thresholdDays < 0 {
+		return errors.New("cert_expiry_threshold_days must not be negative")
+	}
+
+	switch action {
+	case "", checker.CertExpiryWarn, checker.CertExpiryFail:
+		return nil
+	default:
+		return errors.New("cert_expiry_action must be warn or fail")
+	}
+}
+
+func certExpiryThresholdDays(days int) int32 {
+	if days == 0 {
+		return defaultCertExpiryThresholdDays
+	}
+	return int32(days)
+}
+
+func certExpiryAction(action string) string {
+	if action == "" {
+		return checker.CertExpiryWarn
+	}
+	return action
+}
+
 // Create monitor
 func (s *Server) handleCreateMonitor() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -133,17 +379,69 @@ func (s *Server) handleCreateMonitor() http.Handler {
 			}
 		}
 
+		var pingToken pgtype.Text
+		if req.Type == checker.TypeHeartbeat {
+			token, err := newPingToken()
+			if err != nil {
+				respondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			pingToken = pgtype.Text{String: token, Valid: true}
+		}
+
+		// The DSN is only ever stored encrypted
+		var sealedDsn []byte
+		if req.Dsn != "" {
+			sealedDsn, err = s.secrets.Seal([]byte(req.Dsn))
+			if err != nil {
+				respondSecretError(w, r, err)
+				return
+			}
+		}
+
+		var settingsJSON []byte
+		if req.Settings != nil {
+			settingsJSON, err = json.Marshal(req.Settings)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("invalid settings format"))
+				return
+			}
+		}
+
+		var assertionsJSON []byte
+		if len(req.Assertions) > 0 {
+			assertionsJSON, err = json.Marshal(req.Assertions)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("invalid assertions format"))
+				return
+			}
+		}
+
 		mon, err := s.store.CreateMonitor(ctx, storage.CreateMonitorParams{
-			UserID:             int32(userID),
-			Name:               req.Name,
-			Url:                req.Url,
-			Method:             req.Method,
-			IntervalSeconds:    int32(req.IntervalSeconds),
-			TimeoutSeconds:     int32(req.TimeoutSeconds),
-			ExpectedStatusCode: pgtype.Int4{Int32: int32(req.ExpectedStatusCode), Valid: true},
-			Status:             req.Status,
-			Headers:            headersJSON,
-			Body:               pgtype.Text{String: req.Body, Valid: true},
+			UserID:                  int32(userID),
+			Name:                    req.Name,
+			Url:                     req.Url,
+			Method:                  req.Method,
+			IntervalSeconds:         int32(req.IntervalSeconds),
+			TimeoutSeconds:          int32(req.TimeoutSeconds),
+			ExpectedStatusCode:      pgtype.Int4{Int32: int32(req.ExpectedStatusCode), Valid: true},
+			Status:                  req.Status,
+			Headers:                 headersJSON,
+			Body:                    pgtype.Text{String: req.Body, Valid: true},
+			SkipTlsVerify:           req.SkipTlsVerify,
+			ProxyUrl:                pgtype.Text{String: req.ProxyUrl, Valid: req.ProxyUrl != ""},
+			FreshConnection:         req.FreshConnection,
+			Assertions:              assertionsJSON,
+			CertExpiryThresholdDays: certExpiryThresholdDays(req.CertExpiryThresholdDays),
+			CertExpiryAction:        certExpiryAction(req.CertExpiryAction),
+			Type:                    monitorType(req.Type),
+			Settings:                settingsJSON,
+			PingToken:               pingToken,
+			GraceSeconds:            graceSeconds(req.GraceSeconds),
+			Secret:                  sealedDsn,
+			Retries:                 int32(req.Retries),
+			FailureThreshold:        threshold(req.FailureThreshold),
+			RecoveryThreshold:       threshold(req.RecoveryThreshold),
 		})
 
 		if err != nil {
@@ -151,6 +449,8 @@ func (s *Server) handleCreateMonitor() http.Handler {
 			return
 		}
 
+		s.notifyMonitorChanged(ctx, mon.ID)
+
 		// Convert to response format
 		resp, err := toMonitorResponse(mon)
 		if err != nil {
@@ -279,14 +579,27 @@ func (s *Server) ListMonitorsByStatus() http.Handler {
 }
 
 type updateMonitorRequest struct {
-	Name               string         `json:"name"`
-	Url                string         `json:"url"`
-	Method             string         `json:"method"`
-	IntervalSeconds    int16          `json:"interval_seconds"`
-	TimeoutSeconds     int16          `json:"timeout_seconds"`
-	Headers            map[string]any `json:"headers"`
-	Body               string         `json:"body"`
-	ExpectedStatusCode int            `json:"expected_status_code"`
+	Name                    string              `json:"name"`
+	Url                     string              `json:"url"`
+	Method                  string              `json:"method"`
+	IntervalSeconds         int16               `json:"interval_seconds"`
+	TimeoutSeconds          int16               `json:"timeout_seconds"`
+	Headers                 map[string]any      `json:"headers"`
+	Body                    string              `json:"body"`
+	ExpectedStatusCode      int                 `json:"expected_status_code"`
+	SkipTlsVerify           bool                `json:"skip_tls_verify"`
+	ProxyUrl                string              `json:"proxy_url"`
+	FreshConnection         bool                `json:"fresh_connection"`
+	Assertions              []checker.Assertion `json:"assertions"`
+	CertExpiryThresholdDays int                 `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string              `json:"cert_expiry_action"`
+	Type                    string              `json:"type"`
+	Settings                map[string]any      `json:"settings"`
+	GraceSeconds            int                 `json:"grace_seconds"`
+	Dsn                     string              `json:"dsn"`
+	Retries                 int                 `json:"retries"`
+	FailureThreshold        int                 `json:"failure_threshold"`
+	RecoveryThreshold       int                 `json:"recovery_threshold"`
 }
 
 func (r updateMonitorRequest) Valid() error {
@@ -294,12 +607,16 @@ func (r updateMonitorRequest) Valid() error {
 		return errors.New("name is required")
 	}
 
-	if r.Url == "" {
+	if r.Url == "" && r.Type != checker.TypeHeartbeat {
 		return errors.New("url is required")
 	}
 
-	if r.Method == "" {
-		return errors.New("method is required")
+	if r.GraceSeconds < 0 {
+		return errors.New("grace_seconds must not be negative")
+	}
+
+	if err := validateMonitorType(r.Type, r.Url, r.Method, r.Dsn, r.Assertions, r.Settings); err != nil {
+		return err
 	}
 
 	if r.IntervalSeconds < 30 {
@@ -314,6 +631,24 @@ func (r updateMonitorRequest) Valid() error {
 		return errors.New("timeout_seconds must be less than interval_seconds")
 	}
 
+	if err := validateRetries(r.Retries, r.FailureThreshold, r.RecoveryThreshold, r.TimeoutSeconds, r.IntervalSeconds); err != nil {
+		return err
+	}
+
+	if r.ProxyUrl != "" {
+		if _, err := url.Parse(r.ProxyUrl); err != nil {
+			return errors.New("proxy_url must be a valid url")
+		}
+	}
+
+	if err := checker.ValidateAssertions(r.Assertions); err != nil {
+		return err
+	}
+
+	if err := validateCertExpiry(r.CertExpiryThresholdDays, r.CertExpiryAction); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -345,17 +680,89 @@ func (s *Server) handleUpdateMonitor() http.Handler {
 			}
 		}
 
+		// A heartbeat monitor keeps its stored token, so pingers need not be
+		// reconfigured; this one is only used if it has none yet
+		var pingToken pgtype.Text
+		if req.Type == checker.TypeHeartbeat {
+			token, err := newPingToken()
+			if err != nil {
+				respondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			pingToken = pgtype.Text{String: token, Valid: true}
+		}
+
+		// An omitted DSN keeps the stored one, so it is only required when
+		// none is stored yet
+		var sealedDsn []byte
+		if req.Dsn != "" {
+			sealedDsn, err = s.secrets.Seal([]byte(req.Dsn))
+			if err != nil {
+				respondSecretError(w, r, err)
+				return
+			}
+		} else if isDatabaseType(req.Type) {
+			existing, err := s.store.GetMonitorByID(ctx, storage.GetMonitorByIDParams{
+				ID:     int32(id),
+				UserID: int32(userID),
+			})
+			if err != nil {
+				if isNotFound(err) {
+					respondError(w, r, http.StatusNotFound, ErrNotFound)
+					return
+				}
+				respondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+			if len(existing.Secret) == 0 {
+				respondError(w, r, http.StatusBadRequest, errors.New("dsn is required"))
+				return
+			}
+		}
+
+		var settingsJSON []byte
+		if req.Settings != nil {
+			settingsJSON, err = json.Marshal(req.Settings)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("invalid settings format"))
+				return
+			}
+		}
+
+		var assertionsJSON []byte
+		if len(req.Assertions) > 0 {
+			assertionsJSON, err = json.Marshal(req.Assertions)
+			if err != nil {
+				respondError(w, r, http.StatusBadRequest, errors.New("invalid assertions format"))
+				return
+			}
+		}
+
 		mon, err := s.store.UpdateMonitor(ctx, storage.UpdateMonitorParams{
-			ID:                 int32(id),
-			UserID:             int32(userID),
-			Name:               req.Name,
-			Url:                req.Url,
-			Method:             req.Method,
-			IntervalSeconds:    int32(req.IntervalSeconds),
-			TimeoutSeconds:     int32(req.TimeoutSeconds),
-			ExpectedStatusCode: pgtype.Int4{Int32: int32(req.ExpectedStatusCode), Valid: true},
-			Headers:            headersJSON,
-			Body:               pgtype.Text{String: req.Body, Valid: true},
+			ID:                      int32(id),
+			UserID:                  int32(userID),
+			Name:                    req.Name,
+			Url:                     req.Url,
+			Method:                  req.Method,
+			IntervalSeconds:         int32(req.IntervalSeconds),
+			TimeoutSeconds:          int32(req.TimeoutSeconds),
+			ExpectedStatusCode:      pgtype.Int4{Int32: int32(req.ExpectedStatusCode), Valid: true},
+			Headers:                 headersJSON,
+			Body:                    pgtype.Text{String: req.Body, Valid: true},
+			SkipTlsVerify:           req.SkipTlsVerify,
+			ProxyUrl:                pgtype.Text{String: req.ProxyUrl, Valid: req.ProxyUrl != ""},
+			FreshConnection:         req.FreshConnection,
+			Assertions:              assertionsJSON,
+			CertExpiryThresholdDays: certExpiryThresholdDays(req.CertExpiryThresholdDays),
+			CertExpiryAction:        certExpiryAction(req.CertExpiryAction),
+			Type:                    monitorType(req.Type),
+			Settings:                settingsJSON,
+			PingToken:               pingToken,
+			GraceSeconds:            graceSeconds(req.GraceSeconds),
+			Secret:                  sealedDsn,
+			Retries:                 int32(req.Retries),
+			FailureThreshold:        threshold(req.FailureThreshold),
+			RecoveryThreshold:       threshold(req.RecoveryThreshold),
 		})
 
 		if err != nil {
@@ -367,6 +774,8 @@ func (s *Server) handleUpdateMonitor() http.Handler {
 			return
 		}
 
+		s.notifyMonitorChanged(ctx, mon.ID)
+
 		// Convert to response format
 		resp, err := toMonitorResponse(mon)
 		if err != nil {
@@ -433,6 +842,8 @@ func (s *Server) handleUpdateMonitorStatus() http.Handler {
 			return
 		}
 
+		s.notifyMonitorChanged(ctx, int32(id))
+
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
@@ -463,6 +874,16 @@ func (s *Server) handleDeleteMonitor() http.Handler {
 			return
 		}
 
+		s.notifyMonitorChanged(ctx, int32(id))
+
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
+
+// notifyMonitorChanged tells the scheduler to pick up a monitor change. A
+// failed notification is only logged; the change itself has been saved.
+func (s *Server) notifyMonitorChanged(ctx context.Context, id int32) {
+	if err := s.store.NotifyMonitorChanged(ctx, id); err != nil {
+		s.logger.Error("failed to notify monitor change", "monitor_id", id, "error", err)
+	}
+}
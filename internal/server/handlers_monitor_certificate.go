@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rammyblog/monitor-bee/internal/checker"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+type monitorCertificateResponse struct {
+	MonitorID     int32                 `json:"monitor_id"`
+	Subject       string                `json:"subject"`
+	Issuer        string                `json:"issuer"`
+	SANs          []string              `json:"sans"`
+	NotBefore     string                `json:"not_before"`
+	NotAfter      string                `json:"not_after"`
+	DaysRemaining int                   `json:"days_remaining"`
+	ExpiringSoon  bool                  `json:"expiring_soon"`
+	Chain         []checker.Certificate `json:"chain"`
+	CheckedAt     string                `json:"checked_at"`
+}
+
+// GetMonitorCertificate
+func (s *Server) handleGetMonitorCertificate() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+		ctx := r.Context()
+
+		mon, err := s.store.GetMonitorByID(ctx, storage.GetMonitorByIDParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			if isNotFound(err) {
+				respondError(w, r, http.StatusNotFound, ErrNotFound)
+				return
+			}
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		cert, err := s.store.GetMonitorCertificate(ctx, mon.ID)
+		if err != nil {
+			if isNotFound(err) {
+				respondError(w, r, http.StatusNotFound, ErrNotFound)
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		var chain []checker.Certificate
+		if err := json.Unmarshal(cert.Chain, &chain); err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		leaf := checker.Certificate{NotAfter: cert.NotAfter.Time}
+		days := leaf.DaysRemaining(time.Now().UTC())
+
+		respondJSON(w, r, monitorCertificateResponse{
+			MonitorID:     cert.MonitorID,
+			Subject:       cert.Subject,
+			Issuer:        cert.Issuer,
+			SANs:          cert.Sans,
+			NotBefore:     cert.NotBefore.Time.Format("2006-01-02T15:04:05Z07:00"),
+			NotAfter:      cert.NotAfter.Time.Format("2006-01-02T15:04:05Z07:00"),
+			DaysRemaining: days,
+			ExpiringSoon:  days < int(mon.CertExpiryThresholdDays),
+			Chain:         chain,
+			CheckedAt:     cert.CheckedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	})
+}
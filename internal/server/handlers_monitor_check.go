@@ -7,3 +7,150 @@ Delete: DeleteMonitorCheck, DeleteOldMonitorChecks, DeleteMonitorChecksByMonitor
 Analytics: CountMonitorChecks, CountFailedMonitorChecks, CountSuccessfulMonitorChecks, GetAverageResponseTime, GetAverageResponseTimeByDateRange, GetMonitorUptime, GetMonitorUptimeByDateRange, GetMonitorStats
 
 */
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+const (
+	defaultChecksLimit = 50
+	maxChecksLimit     = 500
+)
+
+type checkTimingsResponse struct {
+	DnsMs      int32 `json:"dns_ms"`
+	ConnectMs  int32 `json:"connect_ms"`
+	TlsMs      int32 `json:"tls_ms"`
+	TtfbMs     int32 `json:"ttfb_ms"`
+	TransferMs int32 `json:"transfer_ms"`
+}
+
+type monitorCheckResponse struct {
+	ID             int32                 `json:"id"`
+	MonitorID      int32                 `json:"monitor_id"`
+	Status         string                `json:"status"`
+	ResponseTimeMs int                   `json:"response_time_ms,omitempty"`
+	StatusCode     int                   `json:"status_code,omitempty"`
+	ErrorMessage   string                `json:"error_message,omitempty"`
+	Attempts       int32                 `json:"attempts"`
+	Timings        *checkTimingsResponse `json:"timings,omitempty"`
+	Details        json.RawMessage       `json:"details,omitempty"`
+	CheckedAt      string                `json:"checked_at"`
+}
+
+func toMonitorCheckResponse(check storage.MonitorCheck) monitorCheckResponse {
+	resp := monitorCheckResponse{
+		ID:        check.ID,
+		MonitorID: check.MonitorID,
+		Status:    check.Status,
+		Attempts:  check.Attempts,
+		CheckedAt: check.CheckedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+	}
+
+	if check.ResponseTimeMs.Valid {
+		resp.ResponseTimeMs = int(check.ResponseTimeMs.Int32)
+	}
+
+	if check.StatusCode.Valid {
+		resp.StatusCode = int(check.StatusCode.Int32)
+	}
+
+	if check.ErrorMessage.Valid {
+		resp.ErrorMessage = check.ErrorMessage.String
+	}
+
+	// Timings are recorded together, so one column tells whether they exist
+	if check.TtfbMs.Valid {
+		resp.Timings = &checkTimingsResponse{
+			DnsMs:      check.DnsMs.Int32,
+			ConnectMs:  check.ConnectMs.Int32,
+			TlsMs:      check.TlsMs.Int32,
+			TtfbMs:     check.TtfbMs.Int32,
+			TransferMs: check.TransferMs.Int32,
+		}
+	}
+
+	if len(check.Details) > 0 {
+		resp.Details = check.Details
+	}
+
+	return resp
+}
+
+// ListMonitorChecks
+func (s *Server) handleListMonitorChecks() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		limit, offset, err := pagination(r, defaultChecksLimit, maxChecksLimit)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+		ctx := r.Context()
+
+		owns, err := s.store.UserOwnsMonitor(ctx, storage.UserOwnsMonitorParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !owns {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		checks, err := s.store.ListMonitorChecks(ctx, storage.ListMonitorChecksParams{
+			MonitorID: int32(id),
+			Limit:     limit,
+			Offset:    offset,
+		})
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		responses := make([]monitorCheckResponse, 0, len(checks))
+		for _, c := range checks {
+			responses = append(responses, toMonitorCheckResponse(c))
+		}
+
+		respondJSON(w, r, responses)
+	})
+}
+
+// pagination reads the limit and offset query parameters.
+func pagination(r *http.Request, defaultLimit, maxLimit int32) (int32, int32, error) {
+	limit, offset := defaultLimit, int32(0)
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > int(maxLimit) {
+			return 0, 0, errors.New("limit must be between 1 and " + strconv.Itoa(int(maxLimit)))
+		}
+		limit = int32(n)
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = int32(n)
+	}
+
+	return limit, offset, nil
+}
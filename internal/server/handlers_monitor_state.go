@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rammyblog/monitor-bee/internal/state"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+type monitorStateResponse struct {
+	MonitorID            int32  `json:"monitor_id"`
+	State                string `json:"state"`
+	ConsecutiveFailures  int32  `json:"consecutive_failures"`
+	ConsecutiveSuccesses int32  `json:"consecutive_successes"`
+	LastCheckID          int32  `json:"last_check_id,omitempty"`
+	ChangedAt            string `json:"changed_at,omitempty"`
+	UpdatedAt            string `json:"updated_at,omitempty"`
+}
+
+// GetMonitorState
+func (s *Server) handleGetMonitorState() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidId)
+			return
+		}
+
+		userID := r.Context().Value("userID").(int)
+		ctx := r.Context()
+
+		owns, err := s.store.UserOwnsMonitor(ctx, storage.UserOwnsMonitorParams{
+			ID:     int32(id),
+			UserID: int32(userID),
+		})
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if !owns {
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+			return
+		}
+
+		// A monitor without a recorded check has no state row yet
+		resp := monitorStateResponse{MonitorID: int32(id), State: state.Unknown}
+
+		st, err := s.store.GetMonitorState(ctx, int32(id))
+		if err != nil && !isNotFound(err) {
+			respondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if err == nil {
+			resp.State = st.State
+			resp.ConsecutiveFailures = st.ConsecutiveFailures
+			resp.ConsecutiveSuccesses = st.ConsecutiveSuccesses
+			resp.LastCheckID = st.LastCheckID.Int32
+			if st.ChangedAt.Valid {
+				resp.ChangedAt = st.ChangedAt.Time.Format("2006-01-02T15:04:05Z07:00")
+			}
+			resp.UpdatedAt = st.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00")
+		}
+
+		respondJSON(w, r, resp)
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/rammyblog/monitor-bee/internal/secret"
 )
 
 func decode[T any](r *http.Request) (T, error) {
@@ -48,6 +50,7 @@ var (
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrInvalidId          = errors.New("invalid id")
 	ErrNotFound           = errors.New("resource not found")
+	ErrNoSecretKey        = errors.New("storing secrets requires SECRET_KEY to be set on the server")
 )
 
 func respond(w http.ResponseWriter, r *http.Request, status int, data any) {
@@ -62,6 +65,16 @@ func respondError(w http.ResponseWriter, r *http.Request, status int, err error)
 	})
 }
 
+// respondSecretError reports a failure to seal a secret. Without SECRET_KEY
+// the server cannot store secrets at all, which the client should be told.
+func respondSecretError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, secret.ErrNoKey) {
+		respondError(w, r, http.StatusServiceUnavailable, ErrNoSecretKey)
+		return
+	}
+	respondError(w, r, http.StatusInternalServerError, err)
+}
+
 func respondJSON(w http.ResponseWriter, r *http.Request, data any) {
 	respond(w, r, http.StatusOK, data)
 }
@@ -11,6 +11,11 @@ func (s *Server) routes() http.Handler {
 	mux.Handle("POST /auth/login", s.handleLogin())
 	mux.Handle("POST /auth/register", s.handleRegister())
 
+	// Heartbeat pings, authenticated by the token in the URL
+	mux.Handle("POST /ping/{token}", s.handlePing())
+	mux.Handle("POST /ping/{token}/start", s.handlePingStart())
+	mux.Handle("POST /ping/{token}/fail", s.handlePingFail())
+
 	// Protected routes - apply auth middleware
 	mux.Handle("GET /api/profile", s.authMiddleware(s.handleGetProfile()))
 	mux.Handle("PUT /api/profile", s.authMiddleware(s.handleUpdateProfile()))
@@ -24,6 +29,40 @@ func (s *Server) routes() http.Handler {
 	mux.Handle("PATCH /api/monitors/{id}/status", s.authMiddleware(s.handleUpdateMonitorStatus()))
 	mux.Handle("DELETE /api/monitors/{id}", s.authMiddleware(s.handleDeleteMonitor()))
 
+	// Monitor checks
+	mux.Handle("GET /api/monitors/{id}/checks", s.authMiddleware(s.handleListMonitorChecks()))
+	mux.Handle("GET /api/monitors/{id}/certificate", s.authMiddleware(s.handleGetMonitorCertificate()))
+	mux.Handle("GET /api/monitors/{id}/state", s.authMiddleware(s.handleGetMonitorState()))
+
+	// Incidents
+	mux.Handle("GET /api/incidents", s.authMiddleware(s.handleListIncidents()))
+	mux.Handle("GET /api/incidents/{id}", s.authMiddleware(s.handleGetIncident()))
+	mux.Handle("GET /api/incidents/{id}/timeline", s.authMiddleware(s.handleGetIncidentTimeline()))
+	mux.Handle("POST /api/incidents/{id}/acknowledge", s.authMiddleware(s.handleAcknowledgeIncident()))
+	mux.Handle("POST /api/incidents/{id}/notes", s.authMiddleware(s.handleAddIncidentNote()))
+	mux.Handle("PUT /api/incidents/{id}/postmortem", s.authMiddleware(s.handleUpdateIncidentPostmortem()))
+	mux.Handle("GET /api/monitors/{id}/incidents", s.authMiddleware(s.handleListMonitorIncidents()))
+
+	// Alert channels
+	mux.Handle("POST /api/alert-channels", s.authMiddleware(s.handleCreateAlertChannel()))
+	mux.Handle("GET /api/alert-channels", s.authMiddleware(s.handleListAlertChannels()))
+	mux.Handle("GET /api/alert-channels/{id}", s.authMiddleware(s.handleGetAlertChannel()))
+	mux.Handle("PUT /api/alert-channels/{id}", s.authMiddleware(s.handleUpdateAlertChannel()))
+	mux.Handle("DELETE /api/alert-channels/{id}", s.authMiddleware(s.handleDeleteAlertChannel()))
+	mux.Handle("POST /api/alert-channels/{id}/rotate-secret", s.authMiddleware(s.handleRotateAlertChannelSecret()))
+	mux.Handle("GET /api/alert-channels/{id}/deliveries", s.authMiddleware(s.handleListAlertDeliveries()))
+	mux.Handle("GET /api/alert-channels/{id}/deliveries/{deliveryID}", s.authMiddleware(s.handleGetAlertDelivery()))
+	mux.Handle("GET /api/monitors/{id}/alert-channels", s.authMiddleware(s.handleGetMonitorAlertChannels()))
+	mux.Handle("PUT /api/monitors/{id}/alert-channels", s.authMiddleware(s.handleSetMonitorAlertChannels()))
+
+	// Escalation policies
+	mux.Handle("POST /api/escalation-policies", s.authMiddleware(s.handleCreateEscalationPolicy()))
+	mux.Handle("GET /api/escalation-policies", s.authMiddleware(s.handleListEscalationPolicies()))
+	mux.Handle("GET /api/escalation-policies/{id}", s.authMiddleware(s.handleGetEscalationPolicy()))
+	mux.Handle("PUT /api/escalation-policies/{id}", s.authMiddleware(s.handleUpdateEscalationPolicy()))
+	mux.Handle("DELETE /api/escalation-policies/{id}", s.authMiddleware(s.handleDeleteEscalationPolicy()))
+	mux.Handle("PUT /api/monitors/{id}/escalation-policy", s.authMiddleware(s.handleSetMonitorEscalationPolicy()))
+
 	return s.corsMiddleware(
 		s.loggingMiddleware(
 			s.recoveryMiddleware(mux),
@@ -4,6 +4,10 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/rammyblog/monitor-bee/internal/alert"
+	"github.com/rammyblog/monitor-bee/internal/incident"
+	"github.com/rammyblog/monitor-bee/internal/secret"
+	"github.com/rammyblog/monitor-bee/internal/state"
 	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
 )
 
@@ -11,13 +15,21 @@ type Server struct {
 	store     *storage.Store
 	logger    *slog.Logger
 	jwtSecret string
+	secrets   *secret.Box
+	tracker   *state.Tracker
+	incidents *incident.Service
+	alerts    *alert.Dispatcher
 }
 
-func NewServer(store *storage.Store, logger *slog.Logger, jwtSecret string) *Server {
+func NewServer(store *storage.Store, logger *slog.Logger, jwtSecret string, secrets *secret.Box, tracker *state.Tracker, incidents *incident.Service, alerts *alert.Dispatcher) *Server {
 	return &Server{
 		store:     store,
 		logger:    logger,
 		jwtSecret: jwtSecret,
+		secrets:   secrets,
+		tracker:   tracker,
+		incidents: incidents,
+		alerts:    alerts,
 	}
 }
 
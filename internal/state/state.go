@@ -0,0 +1,140 @@
+// Package state derives whether a monitor is up or down from its checks.
+// A monitor only goes down after failure_threshold failed checks in a row
+// and only comes back up after recovery_threshold successful ones, so a
+// single transient failure does not flip it. The derived state is kept in
+// monitor_states, apart from the raw check history.
+package state
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+// Monitor states.
+const (
+	Unknown = "unknown" // no check has been recorded yet
+	Up      = "up"
+	Down    = "down"
+)
+
+// Transition is a change in a monitor's derived state.
+type Transition struct {
+	Monitor storage.Monitor
+	// Check is the check that caused the change.
+	Check storage.MonitorCheck
+	From  string
+	To    string
+	// State is the saved state after the change.
+	State storage.MonitorState
+}
+
+// Handler is told about every transition in the transaction that saves it,
+// so whatever it records through q is saved along with the transition, or
+// not at all.
+type Handler interface {
+	HandleTransition(ctx context.Context, q *storage.Queries, t Transition) error
+}
+
+// Tracker records checks against monitor states and notifies its handlers
+// of transitions. It is safe for concurrent use once set up.
+type Tracker struct {
+	store    *storage.Store
+	logger   *slog.Logger
+	handlers []Handler
+}
+
+func New(store *storage.Store, logger *slog.Logger) *Tracker {
+	return &Tracker{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Subscribe adds h to the handlers told about transitions. It must be
+// called before the tracker is used.
+func (t *Tracker) Subscribe(h Handler) {
+	t.handlers = append(t.handlers, h)
+}
+
+// Record folds check into mon's state. Handlers are called before the
+// state is committed; an error from any of them rolls the state back and is
+// returned, so the transition is made again on the next check.
+func (t *Tracker) Record(ctx context.Context, mon storage.Monitor, check storage.MonitorCheck) error {
+	var from string
+	var saved storage.MonitorState
+
+	err := t.store.ExecTx(ctx, func(q *storage.Queries) error {
+		if err := q.EnsureMonitorState(ctx, mon.ID); err != nil {
+			return err
+		}
+
+		// The row lock orders concurrent checks of the same monitor, such
+		// as a heartbeat ping racing the missed-ping sweep.
+		cur, err := q.GetMonitorStateForUpdate(ctx, mon.ID)
+		if err != nil {
+			return err
+		}
+		from = cur.State
+
+		next := advance(cur, mon, check.Status == "success")
+		saved, err = q.UpdateMonitorState(ctx, storage.UpdateMonitorStateParams{
+			MonitorID:            mon.ID,
+			State:                next.State,
+			ConsecutiveFailures:  next.ConsecutiveFailures,
+			ConsecutiveSuccesses: next.ConsecutiveSuccesses,
+			LastCheckID:          pgtype.Int4{Int32: check.ID, Valid: true},
+		})
+		if err != nil || saved.State == from {
+			return err
+		}
+
+		tr := Transition{
+			Monitor: mon,
+			Check:   check,
+			From:    from,
+			To:      saved.State,
+			State:   saved,
+		}
+		for _, h := range t.handlers {
+			if err := h.HandleTransition(ctx, q, tr); err != nil {
+				return fmt.Errorf("failed to handle transition to %s: %w", saved.State, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save monitor state: %w", err)
+	}
+
+	if saved.State != from {
+		t.logger.Info("monitor state changed", "monitor_id", mon.ID, "from", from, "to", saved.State)
+	}
+	return nil
+}
+
+// advance returns the state that follows cur after one more check. A
+// monitor with no state yet comes up on its first success, since there is
+// no outage to confirm the end of.
+func advance(cur storage.MonitorState, mon storage.Monitor, success bool) storage.MonitorState {
+	next := cur
+
+	if success {
+		next.ConsecutiveSuccesses++
+		next.ConsecutiveFailures = 0
+		if cur.State == Unknown || next.ConsecutiveSuccesses >= max(mon.RecoveryThreshold, 1) {
+			next.State = Up
+		}
+		return next
+	}
+
+	next.ConsecutiveFailures++
+	next.ConsecutiveSuccesses = 0
+	if next.ConsecutiveFailures >= max(mon.FailureThreshold, 1) {
+		next.State = Down
+	}
+	return next
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"slices"
+	"testing"
+
+	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
+)
+
+func TestAdvance(t *testing.T) {
+	tests := []struct {
+		name              string
+		failureThreshold  int32
+		recoveryThreshold int32
+		start             string
+		// checks are the outcomes of successive checks, true for success
+		checks []bool
+		// want is the state after each check
+		want []string
+	}{
+		{
+			name:  "first success comes up",
+			start: Unknown, failureThreshold: 1, recoveryThreshold: 3,
+			checks: []bool{true},
+			want:   []string{Up},
+		},
+		{
+			name:  "first failure below threshold stays unknown",
+			start: Unknown, failureThreshold: 2, recoveryThreshold: 1,
+			checks: []bool{false, false},
+			want:   []string{Unknown, Down},
+		},
+		{
+			name:  "threshold of one flips at once",
+			start: Up, failureThreshold: 1, recoveryThreshold: 1,
+			checks: []bool{false, true, false},
+			want:   []string{Down, Up, Down},
+		},
+		{
+			name:  "unset thresholds act as one",
+			start: Up, failureThreshold: 0, recoveryThreshold: 0,
+			checks: []bool{false, true},
+			want:   []string{Down, Up},
+		},
+		{
+			name:  "goes down after consecutive failures",
+			start: Up, failureThreshold: 3, recoveryThreshold: 1,
+			checks: []bool{false, false, false, false},
+			want:   []string{Up, Up, Down, Down},
+		},
+		{
+			name:  "success resets the failure count",
+			start: Up, failureThreshold: 3, recoveryThreshold: 1,
+			checks: []bool{false, false, true, false, false},
+			want:   []string{Up, Up, Up, Up, Up},
+		},
+		{
+			name:  "recovers after consecutive successes",
+			start: Down, failureThreshold: 1, recoveryThreshold: 2,
+			checks: []bool{true, true},
+			want:   []string{Down, Up},
+		},
+		{
+			name:  "failure resets the success count",
+			start: Down, failureThreshold: 1, recoveryThreshold: 2,
+			checks: []bool{true, false, true, true},
+			want:   []string{Down, Down, Down, Up},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := storage.Monitor{FailureThreshold: tt.failureThreshold, RecoveryThreshold: tt.recoveryThreshold}
+			cur := storage.MonitorState{State: tt.start}
+
+			var got []string
+			for _, success := range tt.checks {
+				cur = advance(cur, mon, success)
+				got = append(got, cur.State)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("states = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceCounts(t *testing.T) {
+	mon := storage.Monitor{FailureThreshold: 3, RecoveryThreshold: 3}
+	cur := storage.MonitorState{State: Up, ConsecutiveSuccesses: 5}
+
+	cur = advance(cur, mon, false)
+	if cur.ConsecutiveFailures != 1 || cur.ConsecutiveSuccesses != 0 {
+		t.Errorf("after a failure counts = %d failures, %d successes, want 1, 0", cur.ConsecutiveFailures, cur.ConsecutiveSuccesses)
+	}
+
+	cur = advance(cur, mon, true)
+	if cur.ConsecutiveFailures != 0 || cur.ConsecutiveSuccesses != 1 {
+		t.Errorf("after a success counts = %d failures, %d successes, want 0, 1", cur.ConsecutiveFailures, cur.ConsecutiveSuccesses)
+	}
+}
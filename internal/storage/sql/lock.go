@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock held on a dedicated
+// connection. Postgres releases it when the connection closes, so a crashed
+// holder never keeps it.
+type AdvisoryLock struct {
+	conn *pgx.Conn
+}
+
+// TryAdvisoryLock takes the advisory lock identified by key without waiting.
+// It returns a nil lock if another session already holds it.
+func (s *Store) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	pooled, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := pooled.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		pooled.Release()
+		return nil, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !locked {
+		pooled.Release()
+		return nil, nil
+	}
+
+	// The session now owns the lock, so it must never go back to the pool.
+	return &AdvisoryLock{conn: pooled.Hijack()}, nil
+}
+
+// Ping reports whether the session holding the lock is still alive.
+func (l *AdvisoryLock) Ping(ctx context.Context) error {
+	return l.conn.Ping(ctx)
+}
+
+// Release gives up the lock by closing its session.
+func (l *AdvisoryLock) Release() {
+	l.conn.Close(context.Background())
+}
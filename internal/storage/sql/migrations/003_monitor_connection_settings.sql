@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN skip_tls_verify BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN proxy_url VARCHAR(500),
+    ADD COLUMN fresh_connection BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS fresh_connection,
+    DROP COLUMN IF EXISTS proxy_url,
+    DROP COLUMN IF EXISTS skip_tls_verify;
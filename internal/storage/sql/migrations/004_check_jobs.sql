@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE check_jobs(
+    id BIGSERIAL PRIMARY KEY,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_check_jobs_status_run_at ON check_jobs(status, run_at);
+-- At most one open job per monitor, so a slow check is never stacked up behind itself.
+CREATE UNIQUE INDEX idx_check_jobs_open_monitor_id ON check_jobs(monitor_id) WHERE status IN ('pending', 'running');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_check_jobs_open_monitor_id;
+DROP INDEX IF EXISTS idx_check_jobs_status_run_at;
+DROP TABLE IF EXISTS check_jobs;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE monitor_checks
+    ADD COLUMN dns_ms INTEGER,
+    ADD COLUMN connect_ms INTEGER,
+    ADD COLUMN tls_ms INTEGER,
+    ADD COLUMN ttfb_ms INTEGER,
+    ADD COLUMN transfer_ms INTEGER;
+
+-- +goose Down
+ALTER TABLE monitor_checks
+    DROP COLUMN IF EXISTS transfer_ms,
+    DROP COLUMN IF EXISTS ttfb_ms,
+    DROP COLUMN IF EXISTS tls_ms,
+    DROP COLUMN IF EXISTS connect_ms,
+    DROP COLUMN IF EXISTS dns_ms;
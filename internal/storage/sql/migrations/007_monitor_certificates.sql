@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN cert_expiry_threshold_days INTEGER NOT NULL DEFAULT 14,
+    ADD COLUMN cert_expiry_action VARCHAR(10) NOT NULL DEFAULT 'warn';
+
+CREATE TABLE monitor_certificates(
+    monitor_id INTEGER PRIMARY KEY REFERENCES monitors(id) ON DELETE CASCADE,
+    subject VARCHAR(500) NOT NULL,
+    issuer VARCHAR(500) NOT NULL,
+    sans TEXT[] NOT NULL,
+    not_before TIMESTAMP NOT NULL,
+    not_after TIMESTAMP NOT NULL,
+    chain JSONB NOT NULL,
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_certificates;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS cert_expiry_action,
+    DROP COLUMN IF EXISTS cert_expiry_threshold_days;
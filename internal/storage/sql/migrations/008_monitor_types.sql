@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'http',
+    ADD COLUMN settings JSONB;
+
+CREATE INDEX idx_monitors_type ON monitors(type);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_monitors_type;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS settings,
+    DROP COLUMN IF EXISTS type;
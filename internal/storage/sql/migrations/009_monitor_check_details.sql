@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE monitor_checks
+    ADD COLUMN details JSONB;
+
+-- +goose Down
+ALTER TABLE monitor_checks
+    DROP COLUMN IF EXISTS details;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN ping_token VARCHAR(64) UNIQUE,
+    ADD COLUMN grace_seconds INTEGER NOT NULL DEFAULT 60,
+    ADD COLUMN last_ping_at TIMESTAMP,
+    ADD COLUMN ping_started_at TIMESTAMP,
+    ADD COLUMN ping_deadline TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS ping_deadline,
+    DROP COLUMN IF EXISTS ping_started_at,
+    DROP COLUMN IF EXISTS last_ping_at,
+    DROP COLUMN IF EXISTS grace_seconds,
+    DROP COLUMN IF EXISTS ping_token;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN secret BYTEA;
+
+-- +goose Down
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS secret;
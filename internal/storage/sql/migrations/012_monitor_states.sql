@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE monitors
+    ADD COLUMN retries INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN failure_threshold INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN recovery_threshold INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE monitor_checks
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE monitor_states(
+    monitor_id INTEGER PRIMARY KEY REFERENCES monitors(id) ON DELETE CASCADE,
+    state VARCHAR(10) NOT NULL DEFAULT 'unknown',
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    consecutive_successes INTEGER NOT NULL DEFAULT 0,
+    last_check_id INTEGER REFERENCES monitor_checks(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS monitor_states;
+
+ALTER TABLE monitor_checks
+    DROP COLUMN IF EXISTS attempts;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS recovery_threshold,
+    DROP COLUMN IF EXISTS failure_threshold,
+    DROP COLUMN IF EXISTS retries;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE incidents(
+    id SERIAL PRIMARY KEY,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    state VARCHAR(10) NOT NULL DEFAULT 'open',
+    cause TEXT,
+    started_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    duration_seconds INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A monitor has at most one open incident
+CREATE UNIQUE INDEX idx_incidents_open_monitor_id ON incidents(monitor_id) WHERE state = 'open';
+CREATE INDEX idx_incidents_monitor_id ON incidents(monitor_id);
+CREATE INDEX idx_incidents_started_at ON incidents(started_at);
+
+CREATE TABLE incident_checks(
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    check_id INTEGER NOT NULL REFERENCES monitor_checks(id) ON DELETE CASCADE,
+    PRIMARY KEY (incident_id, check_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS incident_checks;
+DROP TABLE IF EXISTS incidents;
@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE incidents
+    ADD COLUMN acknowledged_at TIMESTAMP,
+    ADD COLUMN acknowledged_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN root_cause TEXT,
+    ADD COLUMN resolution TEXT,
+    ADD COLUMN postmortem TEXT;
+
+CREATE TABLE incident_events(
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    check_id INTEGER REFERENCES monitor_checks(id) ON DELETE SET NULL,
+    message TEXT,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_incident_events_incident_id ON incident_events(incident_id);
+
+-- Give incidents opened before the timeline existed their system events
+INSERT INTO incident_events (incident_id, type, occurred_at)
+SELECT id, 'first_failure', started_at FROM incidents;
+
+INSERT INTO incident_events (incident_id, type, occurred_at)
+SELECT id, 'recovered', resolved_at FROM incidents WHERE resolved_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS incident_events;
+
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS postmortem,
+    DROP COLUMN IF EXISTS resolution,
+    DROP COLUMN IF EXISTS root_cause,
+    DROP COLUMN IF EXISTS acknowledged_by,
+    DROP COLUMN IF EXISTS acknowledged_at;
@@ -0,0 +1,60 @@
+-- +goose Up
+CREATE TABLE alert_channels(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    config JSONB NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alert_channels_user_id ON alert_channels(user_id);
+
+CREATE TABLE monitor_alert_channels(
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    channel_id INTEGER NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+    PRIMARY KEY (monitor_id, channel_id)
+);
+
+CREATE INDEX idx_monitor_alert_channels_channel_id ON monitor_alert_channels(channel_id);
+
+-- Queue of notifications to send, worked like check_jobs
+CREATE TABLE alert_deliveries(
+    id BIGSERIAL PRIMARY KEY,
+    channel_id INTEGER NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    check_id INTEGER REFERENCES monitor_checks(id) ON DELETE SET NULL,
+    event VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alert_deliveries_status_run_at ON alert_deliveries(status, run_at);
+CREATE INDEX idx_alert_deliveries_channel_id ON alert_deliveries(channel_id);
+
+CREATE TABLE alert_delivery_attempts(
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL REFERENCES alert_deliveries(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    error TEXT,
+    duration_ms INTEGER NOT NULL,
+    attempted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alert_delivery_attempts_delivery_id ON alert_delivery_attempts(delivery_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS alert_delivery_attempts;
+DROP TABLE IF EXISTS alert_deliveries;
+DROP TABLE IF EXISTS monitor_alert_channels;
+DROP TABLE IF EXISTS alert_channels;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Signing secret of a webhook channel, sealed like monitors.secret
+ALTER TABLE alert_channels
+    ADD COLUMN secret BYTEA;
+
+-- +goose Down
+ALTER TABLE alert_channels
+    DROP COLUMN IF EXISTS secret;
@@ -0,0 +1,56 @@
+-- +goose Up
+CREATE TABLE escalation_policies(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_escalation_policies_user_id ON escalation_policies(user_id);
+
+-- Levels are numbered from 0 and notified delay_minutes after the incident
+-- started
+CREATE TABLE escalation_levels(
+    id SERIAL PRIMARY KEY,
+    policy_id INTEGER NOT NULL REFERENCES escalation_policies(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    delay_minutes INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (policy_id, position)
+);
+
+CREATE TABLE escalation_level_channels(
+    level_id INTEGER NOT NULL REFERENCES escalation_levels(id) ON DELETE CASCADE,
+    channel_id INTEGER NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+    PRIMARY KEY (level_id, channel_id)
+);
+
+CREATE INDEX idx_escalation_level_channels_channel_id ON escalation_level_channels(channel_id);
+
+ALTER TABLE monitors
+    ADD COLUMN escalation_policy_id INTEGER REFERENCES escalation_policies(id) ON DELETE SET NULL;
+
+-- Progress of an incident through its monitor's policy. next_level is the
+-- next level to notify; the row is kept once stopped to know who was told.
+CREATE TABLE escalations(
+    incident_id INTEGER PRIMARY KEY REFERENCES incidents(id) ON DELETE CASCADE,
+    policy_id INTEGER NOT NULL REFERENCES escalation_policies(id) ON DELETE CASCADE,
+    next_level INTEGER NOT NULL DEFAULT 0,
+    next_at TIMESTAMP NOT NULL,
+    stopped_at TIMESTAMP,
+    stop_reason VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_escalations_next_at ON escalations(next_at) WHERE stopped_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS escalations;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS escalation_policy_id;
+
+DROP TABLE IF EXISTS escalation_level_channels;
+DROP TABLE IF EXISTS escalation_levels;
+DROP TABLE IF EXISTS escalation_policies;
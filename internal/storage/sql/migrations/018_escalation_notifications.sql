@@ -0,0 +1,20 @@
+-- +goose Up
+-- Channels paged while escalating an incident, so the same channels hear
+-- that it is over even if the policy is edited in between
+CREATE TABLE escalation_notifications(
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    channel_id INTEGER NOT NULL REFERENCES alert_channels(id) ON DELETE CASCADE,
+    PRIMARY KEY (incident_id, channel_id)
+);
+
+CREATE INDEX idx_escalation_notifications_channel_id ON escalation_notifications(channel_id);
+
+-- Escalations so far paged the levels before next_level
+INSERT INTO escalation_notifications (incident_id, channel_id)
+SELECT DISTINCT escalations.incident_id, escalation_level_channels.channel_id
+FROM escalations
+JOIN escalation_levels ON escalation_levels.policy_id = escalations.policy_id AND escalation_levels.position < escalations.next_level
+JOIN escalation_level_channels ON escalation_level_channels.level_id = escalation_levels.id;
+
+-- +goose Down
+DROP TABLE IF EXISTS escalation_notifications;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- A delivery without a channel emails the monitor's owner
+ALTER TABLE alert_deliveries
+    ALTER COLUMN channel_id DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM alert_deliveries WHERE channel_id IS NULL;
+
+ALTER TABLE alert_deliveries
+    ALTER COLUMN channel_id SET NOT NULL;
@@ -8,20 +8,174 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AlertChannel struct {
+	ID        int32            `json:"id"`
+	UserID    int32            `json:"user_id"`
+	Name      string           `json:"name"`
+	Type      string           `json:"type"`
+	Config    []byte           `json:"config"`
+	Enabled   bool             `json:"enabled"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	Secret    []byte           `json:"secret"`
+}
+
+type AlertDelivery struct {
+	ID          int64            `json:"id"`
+	ChannelID   pgtype.Int4      `json:"channel_id"`
+	MonitorID   int32            `json:"monitor_id"`
+	CheckID     pgtype.Int4      `json:"check_id"`
+	Event       string           `json:"event"`
+	Status      string           `json:"status"`
+	RunAt       pgtype.Timestamp `json:"run_at"`
+	Attempts    int32            `json:"attempts"`
+	MaxAttempts int32            `json:"max_attempts"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+	LastError   pgtype.Text      `json:"last_error"`
+	DeliveredAt pgtype.Timestamp `json:"delivered_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type AlertDeliveryAttempt struct {
+	ID          int64            `json:"id"`
+	DeliveryID  int64            `json:"delivery_id"`
+	Attempt     int32            `json:"attempt"`
+	Success     bool             `json:"success"`
+	Error       pgtype.Text      `json:"error"`
+	DurationMs  int32            `json:"duration_ms"`
+	AttemptedAt pgtype.Timestamp `json:"attempted_at"`
+}
+
+type CheckJob struct {
+	ID          int64            `json:"id"`
+	MonitorID   int32            `json:"monitor_id"`
+	Status      string           `json:"status"`
+	RunAt       pgtype.Timestamp `json:"run_at"`
+	Attempts    int32            `json:"attempts"`
+	MaxAttempts int32            `json:"max_attempts"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+	LastError   pgtype.Text      `json:"last_error"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type Escalation struct {
+	IncidentID int32            `json:"incident_id"`
+	PolicyID   int32            `json:"policy_id"`
+	NextLevel  int32            `json:"next_level"`
+	NextAt     pgtype.Timestamp `json:"next_at"`
+	StoppedAt  pgtype.Timestamp `json:"stopped_at"`
+	StopReason pgtype.Text      `json:"stop_reason"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type EscalationLevel struct {
+	ID           int32 `json:"id"`
+	PolicyID     int32 `json:"policy_id"`
+	Position     int32 `json:"position"`
+	DelayMinutes int32 `json:"delay_minutes"`
+}
+
+type EscalationLevelChannel struct {
+	LevelID   int32 `json:"level_id"`
+	ChannelID int32 `json:"channel_id"`
+}
+
+type EscalationNotification struct {
+	IncidentID int32 `json:"incident_id"`
+	ChannelID  int32 `json:"channel_id"`
+}
+
+type EscalationPolicy struct {
+	ID        int32            `json:"id"`
+	UserID    int32            `json:"user_id"`
+	Name      string           `json:"name"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type Incident struct {
+	ID              int32            `json:"id"`
+	MonitorID       int32            `json:"monitor_id"`
+	State           string           `json:"state"`
+	Cause           pgtype.Text      `json:"cause"`
+	StartedAt       pgtype.Timestamp `json:"started_at"`
+	ResolvedAt      pgtype.Timestamp `json:"resolved_at"`
+	DurationSeconds pgtype.Int4      `json:"duration_seconds"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	AcknowledgedAt  pgtype.Timestamp `json:"acknowledged_at"`
+	AcknowledgedBy  pgtype.Int4      `json:"acknowledged_by"`
+	RootCause       pgtype.Text      `json:"root_cause"`
+	Resolution      pgtype.Text      `json:"resolution"`
+	Postmortem      pgtype.Text      `json:"postmortem"`
+}
+
+type IncidentCheck struct {
+	IncidentID int32 `json:"incident_id"`
+	CheckID    int32 `json:"check_id"`
+}
+
+type IncidentEvent struct {
+	ID         int32            `json:"id"`
+	IncidentID int32            `json:"incident_id"`
+	Type       string           `json:"type"`
+	UserID     pgtype.Int4      `json:"user_id"`
+	CheckID    pgtype.Int4      `json:"check_id"`
+	Message    pgtype.Text      `json:"message"`
+	OccurredAt pgtype.Timestamp `json:"occurred_at"`
+}
+
 type Monitor struct {
-	ID                 int32            `json:"id"`
-	UserID             int32            `json:"user_id"`
-	Name               string           `json:"name"`
-	Url                string           `json:"url"`
-	Method             string           `json:"method"`
-	IntervalSeconds    int32            `json:"interval_seconds"`
-	TimeoutSeconds     int32            `json:"timeout_seconds"`
-	Status             string           `json:"status"`
-	Headers            []byte           `json:"headers"`
-	Body               pgtype.Text      `json:"body"`
-	ExpectedStatusCode pgtype.Int4      `json:"expected_status_code"`
-	CreatedAt          pgtype.Timestamp `json:"created_at"`
-	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+	ID                      int32            `json:"id"`
+	UserID                  int32            `json:"user_id"`
+	Name                    string           `json:"name"`
+	Url                     string           `json:"url"`
+	Method                  string           `json:"method"`
+	IntervalSeconds         int32            `json:"interval_seconds"`
+	TimeoutSeconds          int32            `json:"timeout_seconds"`
+	Status                  string           `json:"status"`
+	Headers                 []byte           `json:"headers"`
+	Body                    pgtype.Text      `json:"body"`
+	ExpectedStatusCode      pgtype.Int4      `json:"expected_status_code"`
+	CreatedAt               pgtype.Timestamp `json:"created_at"`
+	UpdatedAt               pgtype.Timestamp `json:"updated_at"`
+	SkipTlsVerify           bool             `json:"skip_tls_verify"`
+	ProxyUrl                pgtype.Text      `json:"proxy_url"`
+	FreshConnection         bool             `json:"fresh_connection"`
+	Assertions              []byte           `json:"assertions"`
+	CertExpiryThresholdDays int32            `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string           `json:"cert_expiry_action"`
+	Type                    string           `json:"type"`
+	Settings                []byte           `json:"settings"`
+	PingToken               pgtype.Text      `json:"ping_token"`
+	GraceSeconds            int32            `json:"grace_seconds"`
+	LastPingAt              pgtype.Timestamp `json:"last_ping_at"`
+	PingStartedAt           pgtype.Timestamp `json:"ping_started_at"`
+	PingDeadline            pgtype.Timestamp `json:"ping_deadline"`
+	Secret                  []byte           `json:"secret"`
+	Retries                 int32            `json:"retries"`
+	FailureThreshold        int32            `json:"failure_threshold"`
+	RecoveryThreshold       int32            `json:"recovery_threshold"`
+	EscalationPolicyID      pgtype.Int4      `json:"escalation_policy_id"`
+}
+
+type MonitorAlertChannel struct {
+	MonitorID int32 `json:"monitor_id"`
+	ChannelID int32 `json:"channel_id"`
+}
+
+type MonitorCertificate struct {
+	MonitorID int32            `json:"monitor_id"`
+	Subject   string           `json:"subject"`
+	Issuer    string           `json:"issuer"`
+	Sans      []string         `json:"sans"`
+	NotBefore pgtype.Timestamp `json:"not_before"`
+	NotAfter  pgtype.Timestamp `json:"not_after"`
+	Chain     []byte           `json:"chain"`
+	CheckedAt pgtype.Timestamp `json:"checked_at"`
 }
 
 type MonitorCheck struct {
@@ -32,6 +186,23 @@ type MonitorCheck struct {
 	StatusCode     pgtype.Int4      `json:"status_code"`
 	ErrorMessage   pgtype.Text      `json:"error_message"`
 	CheckedAt      pgtype.Timestamp `json:"checked_at"`
+	DnsMs          pgtype.Int4      `json:"dns_ms"`
+	ConnectMs      pgtype.Int4      `json:"connect_ms"`
+	TlsMs          pgtype.Int4      `json:"tls_ms"`
+	TtfbMs         pgtype.Int4      `json:"ttfb_ms"`
+	TransferMs     pgtype.Int4      `json:"transfer_ms"`
+	Details        []byte           `json:"details"`
+	Attempts       int32            `json:"attempts"`
+}
+
+type MonitorState struct {
+	MonitorID            int32            `json:"monitor_id"`
+	State                string           `json:"state"`
+	ConsecutiveFailures  int32            `json:"consecutive_failures"`
+	ConsecutiveSuccesses int32            `json:"consecutive_successes"`
+	LastCheckID          pgtype.Int4      `json:"last_check_id"`
+	ChangedAt            pgtype.Timestamp `json:"changed_at"`
+	UpdatedAt            pgtype.Timestamp `json:"updated_at"`
 }
 
 type User struct {
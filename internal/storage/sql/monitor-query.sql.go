@@ -77,24 +77,52 @@ INSERT INTO monitors (
     status,
     headers,
     body,
-    expected_status_code
+    expected_status_code,
+    skip_tls_verify,
+    proxy_url,
+    fresh_connection,
+    assertions,
+    cert_expiry_threshold_days,
+    cert_expiry_action,
+    type,
+    settings,
+    ping_token,
+    grace_seconds,
+    secret,
+    retries,
+    failure_threshold,
+    recovery_threshold
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
 )
-RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 `
 
 type CreateMonitorParams struct {
-	UserID             int32       `json:"user_id"`
-	Name               string      `json:"name"`
-	Url                string      `json:"url"`
-	Method             string      `json:"method"`
-	IntervalSeconds    int32       `json:"interval_seconds"`
-	TimeoutSeconds     int32       `json:"timeout_seconds"`
-	Status             string      `json:"status"`
-	Headers            []byte      `json:"headers"`
-	Body               pgtype.Text `json:"body"`
-	ExpectedStatusCode pgtype.Int4 `json:"expected_status_code"`
+	UserID                  int32       `json:"user_id"`
+	Name                    string      `json:"name"`
+	Url                     string      `json:"url"`
+	Method                  string      `json:"method"`
+	IntervalSeconds         int32       `json:"interval_seconds"`
+	TimeoutSeconds          int32       `json:"timeout_seconds"`
+	Status                  string      `json:"status"`
+	Headers                 []byte      `json:"headers"`
+	Body                    pgtype.Text `json:"body"`
+	ExpectedStatusCode      pgtype.Int4 `json:"expected_status_code"`
+	SkipTlsVerify           bool        `json:"skip_tls_verify"`
+	ProxyUrl                pgtype.Text `json:"proxy_url"`
+	FreshConnection         bool        `json:"fresh_connection"`
+	Assertions              []byte      `json:"assertions"`
+	CertExpiryThresholdDays int32       `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string      `json:"cert_expiry_action"`
+	Type                    string      `json:"type"`
+	Settings                []byte      `json:"settings"`
+	PingToken               pgtype.Text `json:"ping_token"`
+	GraceSeconds            int32       `json:"grace_seconds"`
+	Secret                  []byte      `json:"secret"`
+	Retries                 int32       `json:"retries"`
+	FailureThreshold        int32       `json:"failure_threshold"`
+	RecoveryThreshold       int32       `json:"recovery_threshold"`
 }
 
 func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (Monitor, error) {
@@ -109,6 +137,20 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (M
 		arg.Headers,
 		arg.Body,
 		arg.ExpectedStatusCode,
+		arg.SkipTlsVerify,
+		arg.ProxyUrl,
+		arg.FreshConnection,
+		arg.Assertions,
+		arg.CertExpiryThresholdDays,
+		arg.CertExpiryAction,
+		arg.Type,
+		arg.Settings,
+		arg.PingToken,
+		arg.GraceSeconds,
+		arg.Secret,
+		arg.Retries,
+		arg.FailureThreshold,
+		arg.RecoveryThreshold,
 	)
 	var i Monitor
 	err := row.Scan(
@@ -125,6 +167,24 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (M
 		&i.ExpectedStatusCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SkipTlsVerify,
+		&i.ProxyUrl,
+		&i.FreshConnection,
+		&i.Assertions,
+		&i.CertExpiryThresholdDays,
+		&i.CertExpiryAction,
+		&i.Type,
+		&i.Settings,
+		&i.PingToken,
+		&i.GraceSeconds,
+		&i.LastPingAt,
+		&i.PingStartedAt,
+		&i.PingDeadline,
+		&i.Secret,
+		&i.Retries,
+		&i.FailureThreshold,
+		&i.RecoveryThreshold,
+		&i.EscalationPolicyID,
 	)
 	return i, err
 }
@@ -135,11 +195,18 @@ INSERT INTO monitor_checks (
     status,
     response_time_ms,
     status_code,
-    error_message
+    error_message,
+    dns_ms,
+    connect_ms,
+    tls_ms,
+    ttfb_ms,
+    transfer_ms,
+    details,
+    attempts
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+RETURNING id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 `
 
 type CreateMonitorCheckParams struct {
@@ -148,6 +215,13 @@ type CreateMonitorCheckParams struct {
 	ResponseTimeMs pgtype.Int4 `json:"response_time_ms"`
 	StatusCode     pgtype.Int4 `json:"status_code"`
 	ErrorMessage   pgtype.Text `json:"error_message"`
+	DnsMs          pgtype.Int4 `json:"dns_ms"`
+	ConnectMs      pgtype.Int4 `json:"connect_ms"`
+	TlsMs          pgtype.Int4 `json:"tls_ms"`
+	TtfbMs         pgtype.Int4 `json:"ttfb_ms"`
+	TransferMs     pgtype.Int4 `json:"transfer_ms"`
+	Details        []byte      `json:"details"`
+	Attempts       int32       `json:"attempts"`
 }
 
 func (q *Queries) CreateMonitorCheck(ctx context.Context, arg CreateMonitorCheckParams) (MonitorCheck, error) {
@@ -157,6 +231,13 @@ func (q *Queries) CreateMonitorCheck(ctx context.Context, arg CreateMonitorCheck
 		arg.ResponseTimeMs,
 		arg.StatusCode,
 		arg.ErrorMessage,
+		arg.DnsMs,
+		arg.ConnectMs,
+		arg.TlsMs,
+		arg.TtfbMs,
+		arg.TransferMs,
+		arg.Details,
+		arg.Attempts,
 	)
 	var i MonitorCheck
 	err := row.Scan(
@@ -167,6 +248,13 @@ func (q *Queries) CreateMonitorCheck(ctx context.Context, arg CreateMonitorCheck
 		&i.StatusCode,
 		&i.ErrorMessage,
 		&i.CheckedAt,
+		&i.DnsMs,
+		&i.ConnectMs,
+		&i.TlsMs,
+		&i.TtfbMs,
+		&i.TransferMs,
+		&i.Details,
+		&i.Attempts,
 	)
 	return i, err
 }
@@ -262,7 +350,7 @@ func (q *Queries) GetAverageResponseTimeByDateRange(ctx context.Context, arg Get
 }
 
 const getLatestMonitorCheck = `-- name: GetLatestMonitorCheck :one
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
@@ -280,12 +368,19 @@ func (q *Queries) GetLatestMonitorCheck(ctx context.Context, monitorID int32) (M
 		&i.StatusCode,
 		&i.ErrorMessage,
 		&i.CheckedAt,
+		&i.DnsMs,
+		&i.ConnectMs,
+		&i.TlsMs,
+		&i.TtfbMs,
+		&i.TransferMs,
+		&i.Details,
+		&i.Attempts,
 	)
 	return i, err
 }
 
 const getMonitor = `-- name: GetMonitor :one
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE id = $1 LIMIT 1
 `
@@ -307,12 +402,30 @@ func (q *Queries) GetMonitor(ctx context.Context, id int32) (Monitor, error) {
 		&i.ExpectedStatusCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SkipTlsVerify,
+		&i.ProxyUrl,
+		&i.FreshConnection,
+		&i.Assertions,
+		&i.CertExpiryThresholdDays,
+		&i.CertExpiryAction,
+		&i.Type,
+		&i.Settings,
+		&i.PingToken,
+		&i.GraceSeconds,
+		&i.LastPingAt,
+		&i.PingStartedAt,
+		&i.PingDeadline,
+		&i.Secret,
+		&i.Retries,
+		&i.FailureThreshold,
+		&i.RecoveryThreshold,
+		&i.EscalationPolicyID,
 	)
 	return i, err
 }
 
 const getMonitorByID = `-- name: GetMonitorByID :one
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE id = $1 AND user_id = $2 LIMIT 1
 `
@@ -339,12 +452,30 @@ func (q *Queries) GetMonitorByID(ctx context.Context, arg GetMonitorByIDParams)
 		&i.ExpectedStatusCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SkipTlsVerify,
+		&i.ProxyUrl,
+		&i.FreshConnection,
+		&i.Assertions,
+		&i.CertExpiryThresholdDays,
+		&i.CertExpiryAction,
+		&i.Type,
+		&i.Settings,
+		&i.PingToken,
+		&i.GraceSeconds,
+		&i.LastPingAt,
+		&i.PingStartedAt,
+		&i.PingDeadline,
+		&i.Secret,
+		&i.Retries,
+		&i.FailureThreshold,
+		&i.RecoveryThreshold,
+		&i.EscalationPolicyID,
 	)
 	return i, err
 }
 
 const getMonitorCheck = `-- name: GetMonitorCheck :one
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE id = $1 LIMIT 1
 `
@@ -360,6 +491,13 @@ func (q *Queries) GetMonitorCheck(ctx context.Context, id int32) (MonitorCheck,
 		&i.StatusCode,
 		&i.ErrorMessage,
 		&i.CheckedAt,
+		&i.DnsMs,
+		&i.ConnectMs,
+		&i.TlsMs,
+		&i.TtfbMs,
+		&i.TransferMs,
+		&i.Details,
+		&i.Attempts,
 	)
 	return i, err
 }
@@ -436,7 +574,7 @@ func (q *Queries) GetMonitorUptimeByDateRange(ctx context.Context, arg GetMonito
 }
 
 const listActiveMonitors = `-- name: ListActiveMonitors :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE status = 'active'
 ORDER BY created_at DESC
@@ -465,6 +603,24 @@ func (q *Queries) ListActiveMonitors(ctx context.Context) ([]Monitor, error) {
 			&i.ExpectedStatusCode,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
 		); err != nil {
 			return nil, err
 		}
@@ -477,7 +633,7 @@ func (q *Queries) ListActiveMonitors(ctx context.Context) ([]Monitor, error) {
 }
 
 const listFailedMonitorChecks = `-- name: ListFailedMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1 AND status = 'failed'
 ORDER BY checked_at DESC
@@ -506,6 +662,13 @@ func (q *Queries) ListFailedMonitorChecks(ctx context.Context, arg ListFailedMon
 			&i.StatusCode,
 			&i.ErrorMessage,
 			&i.CheckedAt,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.Details,
+			&i.Attempts,
 		); err != nil {
 			return nil, err
 		}
@@ -518,7 +681,7 @@ func (q *Queries) ListFailedMonitorChecks(ctx context.Context, arg ListFailedMon
 }
 
 const listMonitorChecks = `-- name: ListMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
@@ -548,6 +711,13 @@ func (q *Queries) ListMonitorChecks(ctx context.Context, arg ListMonitorChecksPa
 			&i.StatusCode,
 			&i.ErrorMessage,
 			&i.CheckedAt,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.Details,
+			&i.Attempts,
 		); err != nil {
 			return nil, err
 		}
@@ -560,7 +730,7 @@ func (q *Queries) ListMonitorChecks(ctx context.Context, arg ListMonitorChecksPa
 }
 
 const listMonitorChecksByDateRange = `-- name: ListMonitorChecksByDateRange :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
     AND checked_at >= $2
@@ -591,6 +761,13 @@ func (q *Queries) ListMonitorChecksByDateRange(ctx context.Context, arg ListMoni
 			&i.StatusCode,
 			&i.ErrorMessage,
 			&i.CheckedAt,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.Details,
+			&i.Attempts,
 		); err != nil {
 			return nil, err
 		}
@@ -603,7 +780,7 @@ func (q *Queries) ListMonitorChecksByDateRange(ctx context.Context, arg ListMoni
 }
 
 const listMonitors = `-- name: ListMonitors :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 ORDER BY created_at DESC
 `
@@ -631,6 +808,82 @@ func (q *Queries) ListMonitors(ctx context.Context) ([]Monitor, error) {
 			&i.ExpectedStatusCode,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonitorsByIDs = `-- name: ListMonitorsByIDs :many
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
+FROM monitors
+WHERE id = ANY($1::int[])
+`
+
+func (q *Queries) ListMonitorsByIDs(ctx context.Context, ids []int32) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, listMonitorsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Url,
+			&i.Method,
+			&i.IntervalSeconds,
+			&i.TimeoutSeconds,
+			&i.Status,
+			&i.Headers,
+			&i.Body,
+			&i.ExpectedStatusCode,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
 		); err != nil {
 			return nil, err
 		}
@@ -643,7 +896,7 @@ func (q *Queries) ListMonitors(ctx context.Context) ([]Monitor, error) {
 }
 
 const listMonitorsByStatus = `-- name: ListMonitorsByStatus :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE status = $1
 ORDER BY created_at DESC
@@ -672,6 +925,24 @@ func (q *Queries) ListMonitorsByStatus(ctx context.Context, status string) ([]Mo
 			&i.ExpectedStatusCode,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
 		); err != nil {
 			return nil, err
 		}
@@ -684,7 +955,7 @@ func (q *Queries) ListMonitorsByStatus(ctx context.Context, status string) ([]Mo
 }
 
 const listMonitorsByUser = `-- name: ListMonitorsByUser :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -713,6 +984,24 @@ func (q *Queries) ListMonitorsByUser(ctx context.Context, userID int32) ([]Monit
 			&i.ExpectedStatusCode,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
 		); err != nil {
 			return nil, err
 		}
@@ -725,7 +1014,7 @@ func (q *Queries) ListMonitorsByUser(ctx context.Context, userID int32) ([]Monit
 }
 
 const listMonitorsByUserAndStatus = `-- name: ListMonitorsByUserAndStatus :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE user_id = $1 AND status = $2
 ORDER BY created_at DESC
@@ -759,6 +1048,24 @@ func (q *Queries) ListMonitorsByUserAndStatus(ctx context.Context, arg ListMonit
 			&i.ExpectedStatusCode,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SkipTlsVerify,
+			&i.ProxyUrl,
+			&i.FreshConnection,
+			&i.Assertions,
+			&i.CertExpiryThresholdDays,
+			&i.CertExpiryAction,
+			&i.Type,
+			&i.Settings,
+			&i.PingToken,
+			&i.GraceSeconds,
+			&i.LastPingAt,
+			&i.PingStartedAt,
+			&i.PingDeadline,
+			&i.Secret,
+			&i.Retries,
+			&i.FailureThreshold,
+			&i.RecoveryThreshold,
+			&i.EscalationPolicyID,
 		); err != nil {
 			return nil, err
 		}
@@ -771,7 +1078,7 @@ func (q *Queries) ListMonitorsByUserAndStatus(ctx context.Context, arg ListMonit
 }
 
 const listRecentMonitorChecks = `-- name: ListRecentMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
@@ -800,6 +1107,13 @@ func (q *Queries) ListRecentMonitorChecks(ctx context.Context, arg ListRecentMon
 			&i.StatusCode,
 			&i.ErrorMessage,
 			&i.CheckedAt,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.Details,
+			&i.Attempts,
 		); err != nil {
 			return nil, err
 		}
@@ -832,22 +1146,50 @@ SET name = $2,
     headers = $7,
     body = $8,
     expected_status_code = $9,
+    skip_tls_verify = $11,
+    proxy_url = $12,
+    fresh_connection = $13,
+    assertions = $14,
+    cert_expiry_threshold_days = $15,
+    cert_expiry_action = $16,
+    type = $17,
+    settings = $18,
+    ping_token = CASE WHEN $19::text IS NULL THEN NULL ELSE COALESCE(ping_token, $19) END,
+    grace_seconds = $20,
+    secret = CASE WHEN $17::text IN ('postgres', 'mysql') THEN COALESCE($21, secret) ELSE NULL END,
+    retries = $22,
+    failure_threshold = $23,
+    recovery_threshold = $24,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $10
-RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 `
 
 type UpdateMonitorParams struct {
-	ID                 int32       `json:"id"`
-	Name               string      `json:"name"`
-	Url                string      `json:"url"`
-	Method             string      `json:"method"`
-	IntervalSeconds    int32       `json:"interval_seconds"`
-	TimeoutSeconds     int32       `json:"timeout_seconds"`
-	Headers            []byte      `json:"headers"`
-	Body               pgtype.Text `json:"body"`
-	ExpectedStatusCode pgtype.Int4 `json:"expected_status_code"`
-	UserID             int32       `json:"user_id"`
+	ID                      int32       `json:"id"`
+	Name                    string      `json:"name"`
+	Url                     string      `json:"url"`
+	Method                  string      `json:"method"`
+	IntervalSeconds         int32       `json:"interval_seconds"`
+	TimeoutSeconds          int32       `json:"timeout_seconds"`
+	Headers                 []byte      `json:"headers"`
+	Body                    pgtype.Text `json:"body"`
+	ExpectedStatusCode      pgtype.Int4 `json:"expected_status_code"`
+	UserID                  int32       `json:"user_id"`
+	SkipTlsVerify           bool        `json:"skip_tls_verify"`
+	ProxyUrl                pgtype.Text `json:"proxy_url"`
+	FreshConnection         bool        `json:"fresh_connection"`
+	Assertions              []byte      `json:"assertions"`
+	CertExpiryThresholdDays int32       `json:"cert_expiry_threshold_days"`
+	CertExpiryAction        string      `json:"cert_expiry_action"`
+	Type                    string      `json:"type"`
+	Settings                []byte      `json:"settings"`
+	PingToken               pgtype.Text `json:"ping_token"`
+	GraceSeconds            int32       `json:"grace_seconds"`
+	Secret                  []byte      `json:"secret"`
+	Retries                 int32       `json:"retries"`
+	FailureThreshold        int32       `json:"failure_threshold"`
+	RecoveryThreshold       int32       `json:"recovery_threshold"`
 }
 
 func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (Monitor, error) {
@@ -862,6 +1204,20 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (M
 		arg.Body,
 		arg.ExpectedStatusCode,
 		arg.UserID,
+		arg.SkipTlsVerify,
+		arg.ProxyUrl,
+		arg.FreshConnection,
+		arg.Assertions,
+		arg.CertExpiryThresholdDays,
+		arg.CertExpiryAction,
+		arg.Type,
+		arg.Settings,
+		arg.PingToken,
+		arg.GraceSeconds,
+		arg.Secret,
+		arg.Retries,
+		arg.FailureThreshold,
+		arg.RecoveryThreshold,
 	)
 	var i Monitor
 	err := row.Scan(
@@ -878,6 +1234,24 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (M
 		&i.ExpectedStatusCode,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SkipTlsVerify,
+		&i.ProxyUrl,
+		&i.FreshConnection,
+		&i.Assertions,
+		&i.CertExpiryThresholdDays,
+		&i.CertExpiryAction,
+		&i.Type,
+		&i.Settings,
+		&i.PingToken,
+		&i.GraceSeconds,
+		&i.LastPingAt,
+		&i.PingStartedAt,
+		&i.PingDeadline,
+		&i.Secret,
+		&i.Retries,
+		&i.FailureThreshold,
+		&i.RecoveryThreshold,
+		&i.EscalationPolicyID,
 	)
 	return i, err
 }
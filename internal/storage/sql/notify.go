@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MonitorChangesChannel is the Postgres NOTIFY channel used to announce that
+// a monitor was created, updated, paused or deleted. The payload is the
+// monitor ID.
+const MonitorChangesChannel = "monitor_changes"
+
+func (s *Store) NotifyMonitorChanged(ctx context.Context, monitorID int32) error {
+	_, err := s.db.Exec(ctx, "SELECT pg_notify($1, $2)", MonitorChangesChannel, strconv.Itoa(int(monitorID)))
+	return err
+}
+
+// Listen subscribes to channel on a dedicated connection and calls handle for
+// every notification until ctx is cancelled or the connection fails.
+func (s *Store) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	pooled, err := s.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	// The connection is taken out of the pool so it never goes back to it
+	// with an active LISTEN.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		handle(notification.Payload)
+	}
+}
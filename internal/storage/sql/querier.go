@@ -11,45 +11,139 @@ import (
 )
 
 type Querier interface {
+	AcknowledgeIncident(ctx context.Context, arg AcknowledgeIncidentParams) (Incident, error)
+	AdvanceEscalation(ctx context.Context, arg AdvanceEscalationParams) error
+	AttachIncidentChecks(ctx context.Context, arg AttachIncidentChecksParams) error
+	// Claims due deliveries, leasing them for lease_seconds. A delivery waits
+	// while an older one to the same channel, or to the owner, about the
+	// same monitor is unfinished, so a retried down alert cannot land after
+	// the up that followed it.
+	ClaimAlertDeliveries(ctx context.Context, arg ClaimAlertDeliveriesParams) ([]AlertDelivery, error)
+	ClaimCheckJobs(ctx context.Context, arg ClaimCheckJobsParams) ([]CheckJob, error)
+	// Locks an incident's escalation, along with the incident, if it is
+	// running and its next level is still due. Must be called in a
+	// transaction.
+	ClaimDueEscalation(ctx context.Context, incidentID int32) (ClaimDueEscalationRow, error)
+	ClaimMissedHeartbeats(ctx context.Context) ([]Monitor, error)
+	ClearEscalationLevels(ctx context.Context, policyID int32) error
+	ClearMonitorAlertChannels(ctx context.Context, monitorID int32) error
+	CompleteAlertDelivery(ctx context.Context, id int64) error
+	CompleteCheckJob(ctx context.Context, id int64) error
 	CountActiveMonitorsByUser(ctx context.Context, userID int32) (int64, error)
+	CountDeadCheckJobs(ctx context.Context) (int64, error)
 	CountFailedMonitorChecks(ctx context.Context, monitorID int32) (int64, error)
 	CountMonitorChecks(ctx context.Context, monitorID int32) (int64, error)
 	CountMonitorsByUser(ctx context.Context, userID int32) (int64, error)
 	CountSuccessfulMonitorChecks(ctx context.Context, monitorID int32) (int64, error)
+	CreateAlertChannel(ctx context.Context, arg CreateAlertChannelParams) (AlertChannel, error)
+	CreateAlertDeliveryAttempt(ctx context.Context, arg CreateAlertDeliveryAttemptParams) error
+	CreateEscalationLevel(ctx context.Context, arg CreateEscalationLevelParams) (EscalationLevel, error)
+	CreateEscalationPolicy(ctx context.Context, arg CreateEscalationPolicyParams) (EscalationPolicy, error)
+	CreateIncidentEvent(ctx context.Context, arg CreateIncidentEventParams) (IncidentEvent, error)
 	CreateMonitor(ctx context.Context, arg CreateMonitorParams) (Monitor, error)
 	CreateMonitorCheck(ctx context.Context, arg CreateMonitorCheckParams) (MonitorCheck, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeadLetterCheckJob(ctx context.Context, arg DeadLetterCheckJobParams) error
+	DeadLetterExpiredCheckJobs(ctx context.Context) (int64, error)
+	DeleteAlertChannel(ctx context.Context, arg DeleteAlertChannelParams) (int64, error)
+	DeleteEscalationPolicy(ctx context.Context, arg DeleteEscalationPolicyParams) (int64, error)
 	DeleteMonitor(ctx context.Context, arg DeleteMonitorParams) error
 	DeleteMonitorByID(ctx context.Context, id int32) error
 	DeleteMonitorCheck(ctx context.Context, id int32) error
 	DeleteMonitorChecksByMonitorID(ctx context.Context, monitorID int32) error
 	DeleteOldMonitorChecks(ctx context.Context, checkedAt pgtype.Timestamp) error
 	DeleteUser(ctx context.Context, id int32) error
+	// Queues a delivery of event to every enabled channel linked to the monitor.
+	EnqueueAlertDeliveries(ctx context.Context, arg EnqueueAlertDeliveriesParams) (int64, error)
+	EnqueueCheckJob(ctx context.Context, arg EnqueueCheckJobParams) (int64, error)
+	// Queues a delivery of event to every enabled channel paged during the
+	// monitor's latest incident, so everyone paged hears that it is over.
+	// Channels linked to the monitor are skipped.
+	EnqueueEscalatedDeliveries(ctx context.Context, arg EnqueueEscalatedDeliveriesParams) (int64, error)
+	// Queues a delivery of event to every enabled channel on an escalation
+	// level, recording each as paged for the incident. Channels linked to the
+	// monitor are skipped, as they are told directly.
+	EnqueueEscalationDeliveries(ctx context.Context, arg EnqueueEscalationDeliveriesParams) (int64, error)
+	// Queues a delivery of event to the monitor's owner, outside any channel.
+	EnqueueOwnerAlertDelivery(ctx context.Context, arg EnqueueOwnerAlertDeliveryParams) error
+	EnsureMonitorState(ctx context.Context, monitorID int32) error
+	FailAlertDelivery(ctx context.Context, arg FailAlertDeliveryParams) error
+	FailExpiredAlertDeliveries(ctx context.Context) (int64, error)
+	GetAlertChannel(ctx context.Context, id int32) (AlertChannel, error)
+	GetAlertChannelByUser(ctx context.Context, arg GetAlertChannelByUserParams) (AlertChannel, error)
+	GetAlertDelivery(ctx context.Context, arg GetAlertDeliveryParams) (AlertDelivery, error)
 	GetAverageResponseTime(ctx context.Context, monitorID int32) (float64, error)
 	GetAverageResponseTimeByDateRange(ctx context.Context, arg GetAverageResponseTimeByDateRangeParams) (float64, error)
+	GetEscalation(ctx context.Context, incidentID int32) (Escalation, error)
+	GetEscalationLevel(ctx context.Context, arg GetEscalationLevelParams) (EscalationLevel, error)
+	GetEscalationPolicyByUser(ctx context.Context, arg GetEscalationPolicyByUserParams) (EscalationPolicy, error)
+	GetIncidentByUser(ctx context.Context, arg GetIncidentByUserParams) (Incident, error)
 	GetLatestMonitorCheck(ctx context.Context, monitorID int32) (MonitorCheck, error)
 	GetMonitor(ctx context.Context, id int32) (Monitor, error)
 	GetMonitorByID(ctx context.Context, arg GetMonitorByIDParams) (Monitor, error)
+	GetMonitorByPingToken(ctx context.Context, pingToken pgtype.Text) (Monitor, error)
+	GetMonitorCertificate(ctx context.Context, monitorID int32) (MonitorCertificate, error)
 	GetMonitorCheck(ctx context.Context, id int32) (MonitorCheck, error)
+	GetMonitorState(ctx context.Context, monitorID int32) (MonitorState, error)
+	GetMonitorStateForUpdate(ctx context.Context, monitorID int32) (MonitorState, error)
 	GetMonitorStats(ctx context.Context, monitorID int32) (GetMonitorStatsRow, error)
 	GetMonitorUptime(ctx context.Context, monitorID int32) (int32, error)
 	GetMonitorUptimeByDateRange(ctx context.Context, arg GetMonitorUptimeByDateRangeParams) (int32, error)
 	GetUser(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
+	// Adds the given channels to a level, skipping any not owned by user_id.
+	LinkEscalationLevelChannels(ctx context.Context, arg LinkEscalationLevelChannelsParams) (int64, error)
+	// Links the given channels to a monitor, skipping any not owned by user_id.
+	LinkMonitorAlertChannels(ctx context.Context, arg LinkMonitorAlertChannelsParams) (int64, error)
 	ListActiveMonitors(ctx context.Context) ([]Monitor, error)
+	ListAlertChannelsByUser(ctx context.Context, userID int32) ([]AlertChannel, error)
+	ListAlertDeliveriesByChannel(ctx context.Context, arg ListAlertDeliveriesByChannelParams) ([]AlertDelivery, error)
+	ListAlertDeliveryAttempts(ctx context.Context, deliveryID int64) ([]AlertDeliveryAttempt, error)
+	// Lists the incidents of running escalations whose next level is due,
+	// soonest first.
+	ListDueEscalations(ctx context.Context, limit int32) ([]int32, error)
+	ListEscalationLevelChannels(ctx context.Context, policyID int32) ([]EscalationLevelChannel, error)
+	ListEscalationLevels(ctx context.Context, policyID int32) ([]EscalationLevel, error)
+	ListEscalationPoliciesByUser(ctx context.Context, userID int32) ([]EscalationPolicy, error)
 	ListFailedMonitorChecks(ctx context.Context, arg ListFailedMonitorChecksParams) ([]MonitorCheck, error)
+	ListIncidentChecks(ctx context.Context, incidentID int32) ([]MonitorCheck, error)
+	ListIncidentEvents(ctx context.Context, incidentID int32) ([]ListIncidentEventsRow, error)
+	ListIncidentsByUser(ctx context.Context, arg ListIncidentsByUserParams) ([]Incident, error)
+	ListMonitorAlertChannels(ctx context.Context, monitorID int32) ([]AlertChannel, error)
 	ListMonitorChecks(ctx context.Context, arg ListMonitorChecksParams) ([]MonitorCheck, error)
 	ListMonitorChecksByDateRange(ctx context.Context, arg ListMonitorChecksByDateRangeParams) ([]MonitorCheck, error)
 	ListMonitors(ctx context.Context) ([]Monitor, error)
+	ListMonitorsByIDs(ctx context.Context, ids []int32) ([]Monitor, error)
 	ListMonitorsByStatus(ctx context.Context, status string) ([]Monitor, error)
 	ListMonitorsByUser(ctx context.Context, userID int32) ([]Monitor, error)
 	ListMonitorsByUserAndStatus(ctx context.Context, arg ListMonitorsByUserAndStatusParams) ([]Monitor, error)
 	ListRecentMonitorChecks(ctx context.Context, arg ListRecentMonitorChecksParams) ([]MonitorCheck, error)
+	// The failed checks, up to and including check_id, that took a monitor down.
+	ListTriggeringChecks(ctx context.Context, arg ListTriggeringChecksParams) ([]MonitorCheck, error)
 	ListUsers(ctx context.Context) ([]ListUsersRow, error)
 	MonitorExists(ctx context.Context, id int32) (bool, error)
+	OpenIncident(ctx context.Context, arg OpenIncidentParams) (Incident, error)
+	RecordHeartbeatPing(ctx context.Context, id int32) (pgtype.Int8, error)
+	ReleaseCheckJob(ctx context.Context, id int64) error
+	ResolveIncident(ctx context.Context, arg ResolveIncidentParams) (Incident, error)
+	RetryAlertDelivery(ctx context.Context, arg RetryAlertDeliveryParams) error
+	RetryCheckJob(ctx context.Context, arg RetryCheckJobParams) error
+	SetMonitorEscalationPolicy(ctx context.Context, arg SetMonitorEscalationPolicyParams) (int64, error)
+	// Starts escalating an incident through its monitor's policy, if the
+	// monitor has one with any levels.
+	StartEscalation(ctx context.Context, incidentID int32) error
+	StartHeartbeat(ctx context.Context, id int32) error
+	StopEscalation(ctx context.Context, arg StopEscalationParams) error
+	// Replaces a channel's settings. A NULL secret keeps the stored one, unless
+	// the type changes, which always replaces it.
+	UpdateAlertChannel(ctx context.Context, arg UpdateAlertChannelParams) (AlertChannel, error)
+	UpdateEscalationPolicy(ctx context.Context, arg UpdateEscalationPolicyParams) (EscalationPolicy, error)
+	UpdateIncidentPostmortem(ctx context.Context, arg UpdateIncidentPostmortemParams) (Incident, error)
 	UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (Monitor, error)
+	UpdateMonitorState(ctx context.Context, arg UpdateMonitorStateParams) (MonitorState, error)
 	UpdateMonitorStatus(ctx context.Context, arg UpdateMonitorStatusParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	UpsertMonitorCertificate(ctx context.Context, arg UpsertMonitorCertificateParams) error
 	UserExists(ctx context.Context, id int32) (bool, error)
 	UserOwnsMonitor(ctx context.Context, arg UserOwnsMonitorParams) (bool, error)
 }
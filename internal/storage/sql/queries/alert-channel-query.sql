@@ -0,0 +1,64 @@
+-- name: CreateAlertChannel :one
+INSERT INTO alert_channels (
+    user_id,
+    name,
+    type,
+    config,
+    enabled,
+    secret
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, user_id, name, type, config, enabled, created_at, updated_at, secret;
+
+-- name: GetAlertChannel :one
+SELECT id, user_id, name, type, config, enabled, created_at, updated_at, secret
+FROM alert_channels
+WHERE id = $1 LIMIT 1;
+
+-- name: GetAlertChannelByUser :one
+SELECT id, user_id, name, type, config, enabled, created_at, updated_at, secret
+FROM alert_channels
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListAlertChannelsByUser :many
+SELECT id, user_id, name, type, config, enabled, created_at, updated_at, secret
+FROM alert_channels
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateAlertChannel :one
+-- Replaces a channel's settings. A NULL secret keeps the stored one, unless
+-- the type changes, which always replaces it.
+UPDATE alert_channels
+SET name = $3,
+    type = $4,
+    config = $5,
+    enabled = $6,
+    secret = CASE WHEN type = $4 THEN COALESCE($7, secret) ELSE $7 END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, type, config, enabled, created_at, updated_at, secret;
+
+-- name: DeleteAlertChannel :execrows
+DELETE FROM alert_channels
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListMonitorAlertChannels :many
+SELECT alert_channels.id, alert_channels.user_id, alert_channels.name, alert_channels.type, alert_channels.config, alert_channels.enabled, alert_channels.created_at, alert_channels.updated_at, alert_channels.secret
+FROM alert_channels
+JOIN monitor_alert_channels ON monitor_alert_channels.channel_id = alert_channels.id
+WHERE monitor_alert_channels.monitor_id = $1
+ORDER BY alert_channels.name, alert_channels.id;
+
+-- name: ClearMonitorAlertChannels :exec
+DELETE FROM monitor_alert_channels
+WHERE monitor_id = $1;
+
+-- name: LinkMonitorAlertChannels :execrows
+-- Links the given channels to a monitor, skipping any not owned by user_id.
+INSERT INTO monitor_alert_channels (monitor_id, channel_id)
+SELECT sqlc.arg(monitor_id)::int, id
+FROM alert_channels
+WHERE id = ANY(sqlc.arg(channel_ids)::int[]) AND user_id = sqlc.arg(user_id)
+ON CONFLICT DO NOTHING;
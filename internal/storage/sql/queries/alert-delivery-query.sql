@@ -0,0 +1,107 @@
+-- name: EnqueueAlertDeliveries :execrows
+-- Queues a delivery of event to every enabled channel linked to the monitor.
+INSERT INTO alert_deliveries (channel_id, monitor_id, check_id, event, max_attempts)
+SELECT alert_channels.id, sqlc.arg(monitor_id)::int, sqlc.narg(check_id)::int, sqlc.arg(event)::text, sqlc.arg(max_attempts)::int
+FROM alert_channels
+JOIN monitor_alert_channels ON monitor_alert_channels.channel_id = alert_channels.id
+WHERE monitor_alert_channels.monitor_id = sqlc.arg(monitor_id) AND alert_channels.enabled;
+
+-- name: EnqueueOwnerAlertDelivery :exec
+-- Queues a delivery of event to the monitor's owner, outside any channel.
+INSERT INTO alert_deliveries (channel_id, monitor_id, check_id, event, max_attempts)
+VALUES (NULL, sqlc.arg(monitor_id), sqlc.narg(check_id), sqlc.arg(event), sqlc.arg(max_attempts));
+
+-- name: ClaimAlertDeliveries :many
+-- Claims due deliveries, leasing them for lease_seconds. A delivery waits
+-- while an older one to the same channel, or to the owner, about the
+-- same monitor is unfinished, so a retried down alert cannot land after
+-- the up that followed it.
+UPDATE alert_deliveries
+SET status = 'sending',
+    attempts = attempts + 1,
+    locked_until = CURRENT_TIMESTAMP + sqlc.arg(lease_seconds)::int * INTERVAL '1 second',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT id FROM alert_deliveries
+    WHERE attempts < max_attempts
+        AND ((status = 'pending' AND run_at <= CURRENT_TIMESTAMP)
+            OR (status = 'sending' AND locked_until < CURRENT_TIMESTAMP))
+        AND NOT EXISTS (
+            SELECT 1 FROM alert_deliveries earlier
+            WHERE earlier.channel_id IS NOT DISTINCT FROM alert_deliveries.channel_id
+                AND earlier.monitor_id = alert_deliveries.monitor_id
+                AND earlier.status IN ('pending', 'sending')
+                AND earlier.id < alert_deliveries.id
+        )
+    ORDER BY run_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, channel_id, monitor_id, check_id, event, status, run_at, attempts, max_attempts, locked_until, last_error, delivered_at, created_at, updated_at;
+
+-- name: CompleteAlertDelivery :exec
+UPDATE alert_deliveries
+SET status = 'delivered',
+    last_error = NULL,
+    locked_until = NULL,
+    delivered_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RetryAlertDelivery :exec
+UPDATE alert_deliveries
+SET status = 'pending',
+    run_at = CURRENT_TIMESTAMP + sqlc.arg(retry_in_seconds)::int * INTERVAL '1 second',
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: FailAlertDelivery :exec
+UPDATE alert_deliveries
+SET status = 'failed',
+    last_error = $2,
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: FailExpiredAlertDeliveries :execrows
+UPDATE alert_deliveries
+SET status = 'failed',
+    last_error = COALESCE(last_error, 'lease expired'),
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'sending'
+    AND locked_until < CURRENT_TIMESTAMP
+    AND attempts >= max_attempts;
+
+-- name: CreateAlertDeliveryAttempt :exec
+INSERT INTO alert_delivery_attempts (
+    delivery_id,
+    attempt,
+    success,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListAlertDeliveriesByChannel :many
+SELECT id, channel_id, monitor_id, check_id, event, status, run_at, attempts, max_attempts, locked_until, last_error, delivered_at, created_at, updated_at
+FROM alert_deliveries
+WHERE channel_id = sqlc.arg(channel_id)
+    AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+    AND (sqlc.narg(monitor_id)::int IS NULL OR monitor_id = sqlc.narg(monitor_id))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetAlertDelivery :one
+SELECT id, channel_id, monitor_id, check_id, event, status, run_at, attempts, max_attempts, locked_until, last_error, delivered_at, created_at, updated_at
+FROM alert_deliveries
+WHERE id = $1 AND channel_id = $2 LIMIT 1;
+
+-- name: ListAlertDeliveryAttempts :many
+SELECT id, delivery_id, attempt, success, error, duration_ms, attempted_at
+FROM alert_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt;
@@ -0,0 +1,69 @@
+-- name: EnqueueCheckJob :execrows
+INSERT INTO check_jobs (
+    monitor_id,
+    max_attempts
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (monitor_id) WHERE status IN ('pending', 'running') DO NOTHING;
+
+-- name: ClaimCheckJobs :many
+UPDATE check_jobs
+SET status = 'running',
+    attempts = check_jobs.attempts + 1,
+    locked_until = CURRENT_TIMESTAMP + (monitors.timeout_seconds * (monitors.retries + 1) + sqlc.arg(lease_margin_seconds)::int) * INTERVAL '1 second',
+    updated_at = CURRENT_TIMESTAMP
+FROM monitors
+WHERE monitors.id = check_jobs.monitor_id
+    AND check_jobs.id IN (
+        SELECT id FROM check_jobs
+        WHERE attempts < max_attempts
+            AND ((status = 'pending' AND run_at <= CURRENT_TIMESTAMP)
+                OR (status = 'running' AND locked_until < CURRENT_TIMESTAMP))
+        ORDER BY run_at
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING check_jobs.id, check_jobs.monitor_id, check_jobs.status, check_jobs.run_at, check_jobs.attempts, check_jobs.max_attempts, check_jobs.locked_until, check_jobs.last_error, check_jobs.created_at, check_jobs.updated_at;
+
+-- name: CompleteCheckJob :exec
+DELETE FROM check_jobs
+WHERE id = $1;
+
+-- name: ReleaseCheckJob :exec
+UPDATE check_jobs
+SET status = 'pending',
+    attempts = attempts - 1,
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RetryCheckJob :exec
+UPDATE check_jobs
+SET status = 'pending',
+    run_at = CURRENT_TIMESTAMP + sqlc.arg(retry_in_seconds)::int * INTERVAL '1 second',
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: DeadLetterCheckJob :exec
+UPDATE check_jobs
+SET status = 'dead',
+    last_error = $2,
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeadLetterExpiredCheckJobs :execrows
+UPDATE check_jobs
+SET status = 'dead',
+    last_error = COALESCE(last_error, 'lease expired'),
+    locked_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'running'
+    AND locked_until < CURRENT_TIMESTAMP
+    AND attempts >= max_attempts;
+
+-- name: CountDeadCheckJobs :one
+SELECT COUNT(*) FROM check_jobs WHERE status = 'dead';
@@ -0,0 +1,76 @@
+-- name: CreateEscalationPolicy :one
+INSERT INTO escalation_policies (
+    user_id,
+    name
+) VALUES (
+    $1, $2
+)
+RETURNING id, user_id, name, created_at, updated_at;
+
+-- name: GetEscalationPolicyByUser :one
+SELECT id, user_id, name, created_at, updated_at
+FROM escalation_policies
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListEscalationPoliciesByUser :many
+SELECT id, user_id, name, created_at, updated_at
+FROM escalation_policies
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateEscalationPolicy :one
+UPDATE escalation_policies
+SET name = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, created_at, updated_at;
+
+-- name: DeleteEscalationPolicy :execrows
+DELETE FROM escalation_policies
+WHERE id = $1 AND user_id = $2;
+
+-- name: ClearEscalationLevels :exec
+DELETE FROM escalation_levels
+WHERE policy_id = $1;
+
+-- name: CreateEscalationLevel :one
+INSERT INTO escalation_levels (
+    policy_id,
+    position,
+    delay_minutes
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, policy_id, position, delay_minutes;
+
+-- name: GetEscalationLevel :one
+SELECT id, policy_id, position, delay_minutes
+FROM escalation_levels
+WHERE policy_id = $1 AND position = $2 LIMIT 1;
+
+-- name: ListEscalationLevels :many
+SELECT id, policy_id, position, delay_minutes
+FROM escalation_levels
+WHERE policy_id = $1
+ORDER BY position;
+
+-- name: ListEscalationLevelChannels :many
+SELECT escalation_level_channels.level_id, escalation_level_channels.channel_id
+FROM escalation_level_channels
+JOIN escalation_levels ON escalation_levels.id = escalation_level_channels.level_id
+WHERE escalation_levels.policy_id = $1
+ORDER BY escalation_levels.position, escalation_level_channels.channel_id;
+
+-- name: LinkEscalationLevelChannels :execrows
+-- Adds the given channels to a level, skipping any not owned by user_id.
+INSERT INTO escalation_level_channels (level_id, channel_id)
+SELECT sqlc.arg(level_id)::int, id
+FROM alert_channels
+WHERE id = ANY(sqlc.arg(channel_ids)::int[]) AND user_id = sqlc.arg(user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: SetMonitorEscalationPolicy :execrows
+UPDATE monitors
+SET escalation_policy_id = sqlc.narg(policy_id),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id);
@@ -0,0 +1,95 @@
+-- name: StartEscalation :exec
+-- Starts escalating an incident through its monitor's policy, if the
+-- monitor has one with any levels.
+INSERT INTO escalations (incident_id, policy_id, next_at)
+SELECT incidents.id, escalation_levels.policy_id, incidents.started_at + escalation_levels.delay_minutes * INTERVAL '1 minute'
+FROM incidents
+JOIN monitors ON monitors.id = incidents.monitor_id
+JOIN escalation_levels ON escalation_levels.policy_id = monitors.escalation_policy_id AND escalation_levels.position = 0
+WHERE incidents.id = $1
+ON CONFLICT (incident_id) DO NOTHING;
+
+-- name: StopEscalation :exec
+UPDATE escalations
+SET stopped_at = CURRENT_TIMESTAMP,
+    stop_reason = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE incident_id = $1 AND stopped_at IS NULL;
+
+-- name: ListDueEscalations :many
+-- Lists the incidents of running escalations whose next level is due,
+-- soonest first.
+SELECT incident_id
+FROM escalations
+WHERE stopped_at IS NULL AND next_at <= CURRENT_TIMESTAMP
+ORDER BY next_at
+LIMIT $1;
+
+-- name: ClaimDueEscalation :one
+-- Locks an incident's escalation, along with the incident, if it is
+-- running and its next level is still due. Must be called in a
+-- transaction.
+SELECT escalations.incident_id, escalations.policy_id, escalations.next_level, escalations.next_at, incidents.monitor_id, incidents.state, incidents.started_at, incidents.acknowledged_at
+FROM escalations
+JOIN incidents ON incidents.id = escalations.incident_id
+WHERE escalations.incident_id = $1
+    AND escalations.stopped_at IS NULL
+    AND escalations.next_at <= CURRENT_TIMESTAMP
+FOR UPDATE OF escalations, incidents SKIP LOCKED;
+
+-- name: AdvanceEscalation :exec
+UPDATE escalations
+SET next_level = next_level + 1,
+    next_at = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE incident_id = $1;
+
+-- name: GetEscalation :one
+SELECT incident_id, policy_id, next_level, next_at, stopped_at, stop_reason, created_at, updated_at
+FROM escalations
+WHERE incident_id = $1 LIMIT 1;
+
+-- name: EnqueueEscalationDeliveries :execrows
+-- Queues a delivery of event to every enabled channel on an escalation
+-- level, recording each as paged for the incident. Channels linked to the
+-- monitor are skipped, as they are told directly.
+WITH paged AS (
+    SELECT alert_channels.id
+    FROM alert_channels
+    JOIN escalation_level_channels ON escalation_level_channels.channel_id = alert_channels.id
+    WHERE escalation_level_channels.level_id = sqlc.arg(level_id)
+        AND alert_channels.enabled
+        AND NOT EXISTS (
+            SELECT 1 FROM monitor_alert_channels
+            WHERE monitor_alert_channels.monitor_id = sqlc.arg(monitor_id)
+                AND monitor_alert_channels.channel_id = alert_channels.id
+        )
+), recorded AS (
+    INSERT INTO escalation_notifications (incident_id, channel_id)
+    SELECT sqlc.arg(incident_id)::int, paged.id FROM paged
+    ON CONFLICT DO NOTHING
+)
+INSERT INTO alert_deliveries (channel_id, monitor_id, check_id, event, max_attempts)
+SELECT paged.id, sqlc.arg(monitor_id)::int, sqlc.narg(check_id)::int, sqlc.arg(event)::text, sqlc.arg(max_attempts)::int
+FROM paged;
+
+-- name: EnqueueEscalatedDeliveries :execrows
+-- Queues a delivery of event to every enabled channel paged during the
+-- monitor's latest incident, so everyone paged hears that it is over.
+-- Channels linked to the monitor are skipped.
+INSERT INTO alert_deliveries (channel_id, monitor_id, check_id, event, max_attempts)
+SELECT alert_channels.id, sqlc.arg(monitor_id)::int, sqlc.narg(check_id)::int, sqlc.arg(event)::text, sqlc.arg(max_attempts)::int
+FROM alert_channels
+JOIN escalation_notifications ON escalation_notifications.channel_id = alert_channels.id
+WHERE escalation_notifications.incident_id = (
+        SELECT incidents.id FROM incidents
+        WHERE incidents.monitor_id = sqlc.arg(monitor_id)
+        ORDER BY incidents.started_at DESC
+        LIMIT 1
+    )
+    AND alert_channels.enabled
+    AND NOT EXISTS (
+        SELECT 1 FROM monitor_alert_channels
+        WHERE monitor_alert_channels.monitor_id = sqlc.arg(monitor_id)
+            AND monitor_alert_channels.channel_id = alert_channels.id
+    );
@@ -0,0 +1,30 @@
+-- name: GetMonitorByPingToken :one
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
+FROM monitors
+WHERE ping_token = $1 AND type = 'heartbeat' LIMIT 1;
+
+-- name: StartHeartbeat :exec
+UPDATE monitors
+SET ping_started_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RecordHeartbeatPing :one
+-- The duration since the start ping, if there was one.
+UPDATE monitors
+SET last_ping_at = CURRENT_TIMESTAMP,
+    ping_started_at = NULL,
+    ping_deadline = CURRENT_TIMESTAMP + (monitors.interval_seconds + monitors.grace_seconds) * INTERVAL '1 second'
+FROM (SELECT id, ping_started_at FROM monitors WHERE id = $1 FOR UPDATE) AS previous
+WHERE monitors.id = previous.id
+RETURNING (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - previous.ping_started_at) * 1000)::bigint AS duration_ms;
+
+-- name: ClaimMissedHeartbeats :many
+-- A monitor is overdue once neither a ping nor an edit happened within its
+-- interval plus grace period. Claiming pushes the deadline forward so each
+-- missed period is reported once.
+UPDATE monitors
+SET ping_deadline = CURRENT_TIMESTAMP + (interval_seconds + grace_seconds) * INTERVAL '1 second'
+WHERE type = 'heartbeat'
+    AND status = 'active'
+    AND GREATEST(ping_deadline, updated_at + (interval_seconds + grace_seconds) * INTERVAL '1 second') < CURRENT_TIMESTAMP
+RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id;
@@ -0,0 +1,19 @@
+-- name: CreateIncidentEvent :one
+INSERT INTO incident_events (
+    incident_id,
+    type,
+    user_id,
+    check_id,
+    message,
+    occurred_at
+) VALUES (
+    sqlc.arg(incident_id), sqlc.arg(type), sqlc.narg(user_id), sqlc.narg(check_id), sqlc.narg(message), COALESCE(sqlc.narg(occurred_at), CURRENT_TIMESTAMP)
+)
+RETURNING id, incident_id, type, user_id, check_id, message, occurred_at;
+
+-- name: ListIncidentEvents :many
+SELECT incident_events.id, incident_events.incident_id, incident_events.type, incident_events.user_id, incident_events.check_id, incident_events.message, incident_events.occurred_at, users.name AS user_name
+FROM incident_events
+LEFT JOIN users ON users.id = incident_events.user_id
+WHERE incident_events.incident_id = $1
+ORDER BY incident_events.occurred_at, incident_events.id;
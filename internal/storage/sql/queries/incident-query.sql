@@ -0,0 +1,74 @@
+-- name: OpenIncident :one
+INSERT INTO incidents (
+    monitor_id,
+    cause,
+    started_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (monitor_id) WHERE state = 'open' DO NOTHING
+RETURNING id, monitor_id, state, cause, started_at, resolved_at, duration_seconds, created_at, updated_at, acknowledged_at, acknowledged_by, root_cause, resolution, postmortem;
+
+-- name: AttachIncidentChecks :exec
+INSERT INTO incident_checks (incident_id, check_id)
+SELECT sqlc.arg(incident_id)::int, unnest(sqlc.arg(check_ids)::int[])
+ON CONFLICT DO NOTHING;
+
+-- name: ResolveIncident :one
+UPDATE incidents
+SET state = 'resolved',
+    resolved_at = sqlc.arg(resolved_at)::timestamp,
+    duration_seconds = EXTRACT(EPOCH FROM (sqlc.arg(resolved_at)::timestamp - started_at))::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE monitor_id = sqlc.arg(monitor_id) AND state = 'open'
+RETURNING id, monitor_id, state, cause, started_at, resolved_at, duration_seconds, created_at, updated_at, acknowledged_at, acknowledged_by, root_cause, resolution, postmortem;
+
+-- name: ListTriggeringChecks :many
+-- The failed checks, up to and including check_id, that took a monitor down.
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
+FROM monitor_checks
+WHERE monitor_id = sqlc.arg(monitor_id) AND id <= sqlc.arg(check_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(failures);
+
+-- name: GetIncidentByUser :one
+SELECT incidents.id, incidents.monitor_id, incidents.state, incidents.cause, incidents.started_at, incidents.resolved_at, incidents.duration_seconds, incidents.created_at, incidents.updated_at, incidents.acknowledged_at, incidents.acknowledged_by, incidents.root_cause, incidents.resolution, incidents.postmortem
+FROM incidents
+JOIN monitors ON monitors.id = incidents.monitor_id
+WHERE incidents.id = $1 AND monitors.user_id = $2 LIMIT 1;
+
+-- name: ListIncidentsByUser :many
+SELECT incidents.id, incidents.monitor_id, incidents.state, incidents.cause, incidents.started_at, incidents.resolved_at, incidents.duration_seconds, incidents.created_at, incidents.updated_at, incidents.acknowledged_at, incidents.acknowledged_by, incidents.root_cause, incidents.resolution, incidents.postmortem
+FROM incidents
+JOIN monitors ON monitors.id = incidents.monitor_id
+WHERE monitors.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(monitor_id)::int IS NULL OR incidents.monitor_id = sqlc.narg(monitor_id))
+    AND (sqlc.narg(state)::text IS NULL OR incidents.state = sqlc.narg(state))
+    AND (sqlc.narg(since)::timestamp IS NULL OR incidents.started_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamp IS NULL OR incidents.started_at < sqlc.narg(until))
+ORDER BY incidents.started_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListIncidentChecks :many
+SELECT monitor_checks.id, monitor_checks.monitor_id, monitor_checks.status, monitor_checks.response_time_ms, monitor_checks.status_code, monitor_checks.error_message, monitor_checks.checked_at, monitor_checks.dns_ms, monitor_checks.connect_ms, monitor_checks.tls_ms, monitor_checks.ttfb_ms, monitor_checks.transfer_ms, monitor_checks.details, monitor_checks.attempts
+FROM monitor_checks
+JOIN incident_checks ON incident_checks.check_id = monitor_checks.id
+WHERE incident_checks.incident_id = $1
+ORDER BY monitor_checks.checked_at;
+
+-- name: AcknowledgeIncident :one
+UPDATE incidents
+SET acknowledged_at = CURRENT_TIMESTAMP,
+    acknowledged_by = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND acknowledged_at IS NULL
+RETURNING id, monitor_id, state, cause, started_at, resolved_at, duration_seconds, created_at, updated_at, acknowledged_at, acknowledged_by, root_cause, resolution, postmortem;
+
+-- name: UpdateIncidentPostmortem :one
+UPDATE incidents
+SET root_cause = $2,
+    resolution = $3,
+    postmortem = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, monitor_id, state, cause, started_at, resolved_at, duration_seconds, created_at, updated_at, acknowledged_at, acknowledged_by, root_cause, resolution, postmortem;
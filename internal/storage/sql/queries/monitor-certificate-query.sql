@@ -0,0 +1,25 @@
+-- name: UpsertMonitorCertificate :exec
+INSERT INTO monitor_certificates (
+    monitor_id,
+    subject,
+    issuer,
+    sans,
+    not_before,
+    not_after,
+    chain
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (monitor_id) DO UPDATE
+SET subject = EXCLUDED.subject,
+    issuer = EXCLUDED.issuer,
+    sans = EXCLUDED.sans,
+    not_before = EXCLUDED.not_before,
+    not_after = EXCLUDED.not_after,
+    chain = EXCLUDED.chain,
+    checked_at = CURRENT_TIMESTAMP;
+
+-- name: GetMonitorCertificate :one
+SELECT monitor_id, subject, issuer, sans, not_before, not_after, chain, checked_at
+FROM monitor_certificates
+WHERE monitor_id = $1 LIMIT 1;
@@ -9,47 +9,66 @@ INSERT INTO monitors (
     status,
     headers,
     body,
-    expected_status_code
+    expected_status_code,
+    skip_tls_verify,
+    proxy_url,
+    fresh_connection,
+    assertions,
+    cert_expiry_threshold_days,
+    cert_expiry_action,
+    type,
+    settings,
+    ping_token,
+    grace_seconds,
+    secret,
+    retries,
+    failure_threshold,
+    recovery_threshold
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
 )
-RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at;
+RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id;
 
 -- name: GetMonitor :one
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE id = $1 LIMIT 1;
 
 -- name: GetMonitorByID :one
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE id = $1 AND user_id = $2 LIMIT 1;
 
 -- name: ListMonitors :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 ORDER BY created_at DESC;
 
 -- name: ListMonitorsByUser :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE user_id = $1
 ORDER BY created_at DESC;
 
 -- name: ListActiveMonitors :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE status = 'active'
 ORDER BY created_at DESC;
 
+-- name: ListMonitorsByIDs :many
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
+FROM monitors
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
 -- name: ListMonitorsByStatus :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE status = $1
 ORDER BY created_at DESC;
 
 -- name: ListMonitorsByUserAndStatus :many
-SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at
+SELECT id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id
 FROM monitors
 WHERE user_id = $1 AND status = $2
 ORDER BY created_at DESC;
@@ -64,9 +83,23 @@ SET name = $2,
     headers = $7,
     body = $8,
     expected_status_code = $9,
+    skip_tls_verify = $11,
+    proxy_url = $12,
+    fresh_connection = $13,
+    assertions = $14,
+    cert_expiry_threshold_days = $15,
+    cert_expiry_action = $16,
+    type = $17,
+    settings = $18,
+    ping_token = CASE WHEN $19::text IS NULL THEN NULL ELSE COALESCE(ping_token, $19) END,
+    grace_seconds = $20,
+    secret = CASE WHEN $17::text IN ('postgres', 'mysql') THEN COALESCE($21, secret) ELSE NULL END,
+    retries = $22,
+    failure_threshold = $23,
+    recovery_threshold = $24,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $10
-RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at;
+RETURNING id, user_id, name, url, method, interval_seconds, timeout_seconds, status, headers, body, expected_status_code, created_at, updated_at, skip_tls_verify, proxy_url, fresh_connection, assertions, cert_expiry_threshold_days, cert_expiry_action, type, settings, ping_token, grace_seconds, last_ping_at, ping_started_at, ping_deadline, secret, retries, failure_threshold, recovery_threshold, escalation_policy_id;
 
 -- name: UpdateMonitorStatus :exec
 UPDATE monitors
@@ -101,33 +134,40 @@ INSERT INTO monitor_checks (
     status,
     response_time_ms,
     status_code,
-    error_message
+    error_message,
+    dns_ms,
+    connect_ms,
+    tls_ms,
+    ttfb_ms,
+    transfer_ms,
+    details,
+    attempts
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING id, monitor_id, status, response_time_ms, status_code, error_message, checked_at;
+RETURNING id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts;
 
 -- name: GetMonitorCheck :one
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE id = $1 LIMIT 1;
 
 -- name: GetLatestMonitorCheck :one
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
 LIMIT 1;
 
 -- name: ListMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: ListMonitorChecksByDateRange :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
     AND checked_at >= $2
@@ -135,14 +175,14 @@ WHERE monitor_id = $1
 ORDER BY checked_at DESC;
 
 -- name: ListRecentMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1
 ORDER BY checked_at DESC
 LIMIT $2;
 
 -- name: ListFailedMonitorChecks :many
-SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at
+SELECT id, monitor_id, status, response_time_ms, status_code, error_message, checked_at, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, details, attempts
 FROM monitor_checks
 WHERE monitor_id = $1 AND status = 'failed'
 ORDER BY checked_at DESC
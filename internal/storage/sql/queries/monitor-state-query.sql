@@ -0,0 +1,26 @@
+-- name: EnsureMonitorState :exec
+INSERT INTO monitor_states (monitor_id)
+VALUES ($1)
+ON CONFLICT (monitor_id) DO NOTHING;
+
+-- name: GetMonitorStateForUpdate :one
+SELECT monitor_id, state, consecutive_failures, consecutive_successes, last_check_id, changed_at, updated_at
+FROM monitor_states
+WHERE monitor_id = $1
+FOR UPDATE;
+
+-- name: UpdateMonitorState :one
+UPDATE monitor_states
+SET state = $2,
+    consecutive_failures = $3,
+    consecutive_successes = $4,
+    last_check_id = $5,
+    changed_at = CASE WHEN state <> $2 THEN CURRENT_TIMESTAMP ELSE changed_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE monitor_id = $1
+RETURNING monitor_id, state, consecutive_failures, consecutive_successes, last_check_id, changed_at, updated_at;
+
+-- name: GetMonitorState :one
+SELECT monitor_id, state, consecutive_failures, consecutive_successes, last_check_id, changed_at, updated_at
+FROM monitor_states
+WHERE monitor_id = $1 LIMIT 1;
@@ -8,11 +8,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/rammyblog/monitor-bee/internal/alert"
+	"github.com/rammyblog/monitor-bee/internal/checker"
 	"github.com/rammyblog/monitor-bee/internal/config"
+	"github.com/rammyblog/monitor-bee/internal/incident"
+	"github.com/rammyblog/monitor-bee/internal/scheduler"
+	"github.com/rammyblog/monitor-bee/internal/secret"
 	"github.com/rammyblog/monitor-bee/internal/server"
+	"github.com/rammyblog/monitor-bee/internal/state"
 	storage "github.com/rammyblog/monitor-bee/internal/storage/sql"
 )
 
@@ -37,7 +44,36 @@ func run(args []string) error {
 	}
 	defer store.Close()
 
-	srv := server.NewServer(store, logger, cfg.JWTSecret)
+	secrets, err := secret.New(cfg.SecretKey)
+	if err != nil {
+		return fmt.Errorf("failed to initialize secrets: %w", err)
+	}
+
+	incidents := incident.NewService(store, logger)
+	alerts := alert.NewDispatcher(store, logger)
+	alerts.Register(alert.TypeWebhook, alert.NewWebhookNotifier(secrets, server.RenderAlertEvent))
+	alerts.Register(alert.TypeSlack, alert.NewSlackNotifier(secrets, cfg.AppURL))
+	alerts.Register(alert.TypeDiscord, alert.NewDiscordNotifier(secrets, cfg.AppURL))
+	alerts.Register(alert.TypePagerDuty, alert.NewPagerDutyNotifier(secrets, cfg.PagerDutyURL, cfg.AppURL))
+	alerts.Register(alert.TypeOpsgenie, alert.NewOpsgenieNotifier(secrets, cfg.OpsgenieURL, cfg.AppURL))
+	if cfg.SMTPHost != "" {
+		sender := alert.NewSMTPSender(alert.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		email := alert.NewEmailNotifier(store, sender, cfg.AppURL)
+		alerts.Register(alert.TypeEmail, email)
+		alerts.RegisterOwner(email)
+	}
+	escalator := alert.NewEscalator(store, logger)
+	tracker := state.New(store, logger)
+	tracker.Subscribe(incidents)
+	tracker.Subscribe(alerts)
+
+	srv := server.NewServer(store, logger, cfg.JWTSecret, secrets, tracker, incidents, alerts)
 	httpServer := &http.Server{
 		Addr:         cfg.Port,
 		Handler:      srv.Handler(),
@@ -47,6 +83,31 @@ func run(args []string) error {
 		BaseContext:  func(_ net.Listener) context.Context { return context.Background() },
 	}
 
+	chk := checker.New(secrets)
+	defer chk.Close()
+
+	sched := scheduler.New(store, tracker, logger)
+	worker := scheduler.NewWorker(store, chk, tracker, logger, scheduler.PoolConfig{
+		Workers:    cfg.WorkerConcurrency,
+		PerHost:    cfg.WorkerPerHost,
+		QueueDepth: cfg.WorkerQueueDepth,
+	})
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	var background sync.WaitGroup
+	background.Go(func() { sched.Run(bgCtx) })
+	background.Go(func() { worker.Run(bgCtx) })
+	background.Go(func() { alerts.Run(bgCtx) })
+	background.Go(func() { escalator.Run(bgCtx) })
+
+	bgDone := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(bgDone)
+	}()
+
 	go func() {
 		logger.Info("starting server", "port", cfg.Port)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -62,10 +123,18 @@ func run(args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	stopBackground()
+
 	if err := httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
+	select {
+	case <-bgDone:
+	case <-ctx.Done():
+		return fmt.Errorf("background jobs forced to shutdown: %w", ctx.Err())
+	}
+
 	logger.Info("server exited")
 	return nil
 }